@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"context"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/features"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
+)
+
+// defaultMaxEvictionFraction is the fraction of activePods a single
+// synchronize cycle may evict when Config.MaxEvictionFraction is unset, so
+// a misconfigured EvictionBurst can't drain an entire node in one tick.
+const defaultMaxEvictionFraction = 0.5
+
+// evictRankedPods evicts pods (already ranked by evictRankedPods' caller)
+// starting from the top candidate. With the default configuration it kills
+// at most one pod, preserving today's behavior. With Config.MinReclaimAware
+// set, it re-queries usage after each kill and keeps evicting down the
+// ranked list until the signal recovers past threshold+minReclaim,
+// Config.EvictionBurst pods have been killed, or candidates run out.
+func (m *managerImpl) evictRankedPods(ctx context.Context, rankedPods []*v1.Pod, thresholdToReclaim evictionapi.Threshold, resourceToReclaim v1.ResourceName, statsFunc statsFunc, observations signalObservations, thresholds []evictionapi.Threshold) []*v1.Pod {
+	burst := m.config.EvictionBurst
+	if burst <= 0 {
+		burst = 1
+	}
+	rankedPods = m.applyBreakGlassOrdering(rankedPods, thresholdToReclaim)
+	maxToEvict := maxEvictionCount(len(rankedPods), burst, m.config.MaxEvictionFraction)
+	if maxToEvict > len(rankedPods) {
+		maxToEvict = len(rankedPods)
+	}
+
+	// Without MinReclaimAware there's no need to serialize on re-observing
+	// the signal between kills, so let candidates race on the shared
+	// concurrency budget instead of evicting one at a time.
+	if !m.config.MinReclaimAware && m.config.MaxConcurrentEvictions > 1 {
+		return m.evictConcurrently(ctx, rankedPods, maxToEvict, thresholdToReclaim, resourceToReclaim, statsFunc, observations, thresholds)
+	}
+
+	var evicted []*v1.Pod
+	for i, pod := range rankedPods {
+		if len(evicted) >= maxToEvict {
+			break
+		}
+
+		// Give the top-ranked candidate a gentler tier before killing it:
+		// soft-reclaim only ever applies once per cycle, to the pod that
+		// would otherwise be evicted first. If it recovers the signal, the
+		// whole point was to avoid a kill this cycle, so stop here instead
+		// of falling through to evict the next-ranked pod.
+		if i == 0 && len(evicted) == 0 && m.reclaimPodLevelResources(ctx, pod, thresholdToReclaim) {
+			return nil
+		}
+
+		// Route every eviction attempt, serial or concurrent, through the
+		// same in-flight/rate-limit gate so the per-node evictions-per-minute
+		// limit and the never-double-evict-a-pod guarantee hold regardless of
+		// MinReclaimAware/MaxConcurrentEvictions.
+		if !m.concurrency.tryStart(ctx, pod) {
+			continue
+		}
+
+		gracePeriodOverride := int64(0)
+		if !isHardEvictionThreshold(thresholdToReclaim) {
+			gracePeriodOverride = m.config.MaxPodGracePeriodSeconds
+		}
+		if override, ok := m.rankerGraceOverrideFor(pod.UID); ok {
+			gracePeriodOverride = override
+		}
+		message, annotations := evictionMessage(resourceToReclaim, pod, statsFunc, thresholds, observations)
+		reason := buildEvictionReason(thresholdToReclaim, observedValue(observations, thresholdToReclaim.Signal), rankingRationale(i, len(rankedPods)), m.config.EvictionOnlyByAPI && m.evictionAPIClient != nil)
+		annotations = reason.annotations(annotations)
+		var condition *v1.PodCondition
+		if utilfeature.DefaultFeatureGate.Enabled(features.PodDisruptionConditions) {
+			condition = reason.condition(message)
+		}
+
+		evictedOK := m.evictPodRespectingPDB(ctx, pod, gracePeriodOverride, message, annotations, condition)
+		m.concurrency.finish(pod)
+		if !evictedOK {
+			continue
+		}
+		metrics.Evictions.WithLabelValues(string(thresholdToReclaim.Signal)).Inc()
+		evicted = append(evicted, pod)
+
+		if !m.config.MinReclaimAware || len(evicted) >= burst {
+			break
+		}
+		recovered, err := m.signalRecovered(ctx, thresholdToReclaim)
+		if err != nil {
+			klog.InfoS("Eviction manager: failed to re-observe signal after eviction, stopping this cycle's eviction burst", "signal", thresholdToReclaim.Signal, "err", err)
+			break
+		}
+		if recovered {
+			klog.InfoS("Eviction manager: signal recovered past minReclaim, stopping this cycle's eviction burst", "signal", thresholdToReclaim.Signal, "evicted", len(evicted))
+			break
+		}
+	}
+
+	if len(evicted) == 0 {
+		klog.InfoS("Eviction manager: unable to evict any pods from the node")
+		return nil
+	}
+	return evicted
+}
+
+// evictConcurrently attempts up to maxToEvict of rankedPods in parallel,
+// bounded by m.concurrency's semaphore and per-minute rate limit, and
+// tracked by its in-flight set so the same pod is never evicted twice across
+// overlapping synchronize ticks. Returns the pods actually evicted.
+func (m *managerImpl) evictConcurrently(ctx context.Context, rankedPods []*v1.Pod, maxToEvict int, thresholdToReclaim evictionapi.Threshold, resourceToReclaim v1.ResourceName, statsFunc statsFunc, observations signalObservations, thresholds []evictionapi.Threshold) []*v1.Pod {
+	// Give the top-ranked candidate the same soft-reclaim tier the serial
+	// path does before fanning out concurrent kills; otherwise MaxConcurrentEvictions>1
+	// without MinReclaimAware skips chunk1-6's memory.high tightening entirely.
+	if maxToEvict > 0 && m.reclaimPodLevelResources(ctx, rankedPods[0], thresholdToReclaim) {
+		return nil
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		evicted []*v1.Pod
+	)
+
+	for i, pod := range rankedPods[:maxToEvict] {
+		if !m.concurrency.tryStart(ctx, pod) {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, pod *v1.Pod) {
+			defer wg.Done()
+			defer m.concurrency.finish(pod)
+
+			gracePeriodOverride := int64(0)
+			if !isHardEvictionThreshold(thresholdToReclaim) {
+				gracePeriodOverride = m.config.MaxPodGracePeriodSeconds
+			}
+			if override, ok := m.rankerGraceOverrideFor(pod.UID); ok {
+				gracePeriodOverride = override
+			}
+			message, annotations := evictionMessage(resourceToReclaim, pod, statsFunc, thresholds, observations)
+			reason := buildEvictionReason(thresholdToReclaim, observedValue(observations, thresholdToReclaim.Signal), rankingRationale(i, len(rankedPods)), m.config.EvictionOnlyByAPI && m.evictionAPIClient != nil)
+			annotations = reason.annotations(annotations)
+			var condition *v1.PodCondition
+			if utilfeature.DefaultFeatureGate.Enabled(features.PodDisruptionConditions) {
+				condition = reason.condition(message)
+			}
+
+			if !m.evictPodRespectingPDB(ctx, pod, gracePeriodOverride, message, annotations, condition) {
+				return
+			}
+			metrics.Evictions.WithLabelValues(string(thresholdToReclaim.Signal)).Inc()
+			mu.Lock()
+			evicted = append(evicted, pod)
+			mu.Unlock()
+		}(i, pod)
+	}
+	wg.Wait()
+
+	if len(evicted) == 0 {
+		klog.InfoS("Eviction manager: unable to evict any pods from the node")
+		return nil
+	}
+	return evicted
+}
+
+// signalRecovered re-queries summaryProvider and reports whether
+// thresholdToReclaim, including its minReclaim goal, is no longer met. It
+// keeps the already-computed rank order and only refreshes the signal being
+// reclaimed, so the check stays cheap between evictions in the same cycle.
+func (m *managerImpl) signalRecovered(ctx context.Context, thresholdToReclaim evictionapi.Threshold) (bool, error) {
+	summary, err := m.summaryProvider.Get(ctx, true)
+	if err != nil {
+		return false, err
+	}
+	observations, _ := makeSignalObservations(summary)
+	addPSIObservations(observations, m.psiProvider)
+	stillMet := thresholdsMet([]evictionapi.Threshold{thresholdToReclaim}, observations, true)
+	return len(stillMet) == 0, nil
+}
+
+// maxEvictionCount returns the smaller of burst and maxFraction (defaulting
+// to defaultMaxEvictionFraction when unset) of activeCount. The fraction is
+// rounded to the nearest whole pod and never drops below 1, so the safeguard
+// never blocks the very first eviction of a cycle.
+func maxEvictionCount(activeCount, burst int, maxFraction float64) int {
+	if maxFraction <= 0 {
+		maxFraction = defaultMaxEvictionFraction
+	}
+	fractionCap := int(float64(activeCount)*maxFraction + 0.5)
+	if fractionCap < 1 {
+		fractionCap = 1
+	}
+	if burst < fractionCap {
+		return burst
+	}
+	return fractionCap
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+)
+
+// preventNodePressureEvictionAnnotation lets a pod opt out of node-pressure
+// eviction entirely, the same escape hatch ecosystem evicters ship for
+// tainted-pod workflows, without requiring a PDB or a priority-class change.
+const preventNodePressureEvictionAnnotation = "kubelet.kubernetes.io/prevent-node-pressure-eviction"
+
+// hasPreventEvictionAnnotation reports whether pod carries the break-glass
+// annotation set to "true".
+func hasPreventEvictionAnnotation(pod *v1.Pod) bool {
+	return pod.Annotations[preventNodePressureEvictionAnnotation] == "true"
+}
+
+// applyBreakGlassOrdering partitions rankedPods (without otherwise
+// reordering them) into pods eligible for node-pressure eviction and pods
+// marked with the break-glass annotation. Under a soft threshold the
+// protected pods are dropped entirely; under a hard threshold they're kept,
+// but moved to the back of the list, so the kubelet still kills something to
+// relieve pressure rather than stalling the node indefinitely.
+func (m *managerImpl) applyBreakGlassOrdering(rankedPods []*v1.Pod, threshold evictionapi.Threshold) []*v1.Pod {
+	var eligible, protected []*v1.Pod
+	for _, pod := range rankedPods {
+		if hasPreventEvictionAnnotation(pod) {
+			protected = append(protected, pod)
+			continue
+		}
+		eligible = append(eligible, pod)
+	}
+	if len(protected) == 0 {
+		return eligible
+	}
+
+	if !isHardEvictionThreshold(threshold) {
+		for _, pod := range protected {
+			klog.InfoS("Eviction manager: preserving pod marked with the break-glass annotation", "pod", klog.KObj(pod), "signal", threshold.Signal)
+			m.recorder.Eventf(pod, v1.EventTypeNormal, "PreservedByBreakGlass",
+				"Pod preserved from node-pressure eviction because it carries the %s annotation", preventNodePressureEvictionAnnotation)
+		}
+		return eligible
+	}
+
+	if len(eligible) == 0 {
+		m.recorder.Eventf(m.nodeRef, v1.EventTypeWarning, "EvictingProtectedPod",
+			"All eviction candidates are marked %s; falling back to evicting a protected pod to relieve hard %s pressure",
+			preventNodePressureEvictionAnnotation, threshold.Signal)
+	}
+	return append(eligible, protected...)
+}
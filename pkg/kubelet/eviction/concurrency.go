@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
+)
+
+// evictionConcurrency tracks the in-flight-eviction semaphore (sized by
+// --max-concurrent-evictions), the set of pods currently being evicted so
+// the same pod is never double-evicted across synchronize ticks, and a
+// per-node "evictions per minute" rate limit analogous to descheduler's
+// maxPodsToEvict.
+type evictionConcurrency struct {
+	sem *semaphore.Weighted
+
+	mu        sync.Mutex
+	inFlight  map[types.UID]struct{}
+	recent    []time.Time
+	perMinute int
+}
+
+// newEvictionConcurrency builds the concurrency tracker for maxConcurrent
+// in-flight evictPod calls (defaulting to 1, i.e. serial, for backward
+// compatibility) and perMinute evictions (0 means unlimited).
+func newEvictionConcurrency(maxConcurrent, perMinute int) *evictionConcurrency {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	return &evictionConcurrency{
+		sem:       semaphore.NewWeighted(int64(maxConcurrent)),
+		inFlight:  make(map[types.UID]struct{}),
+		perMinute: perMinute,
+	}
+}
+
+// tryStart reserves a concurrency slot and a rate-limit token for pod,
+// returning false (and reserving nothing) if the pod is already being
+// evicted or the per-minute budget is exhausted. On true, the caller must
+// call finish(pod) once the eviction attempt completes.
+func (e *evictionConcurrency) tryStart(ctx context.Context, pod *v1.Pod) bool {
+	if !e.reserve(pod) {
+		return false
+	}
+
+	// Acquire outside e.mu: with maxConcurrent goroutines already holding
+	// slots, this blocks until one of them calls finish(), which itself needs
+	// e.mu just to delete its inFlight entry. Holding e.mu across Acquire
+	// would deadlock finish() against it.
+	if err := e.sem.Acquire(ctx, 1); err != nil {
+		e.mu.Lock()
+		delete(e.inFlight, pod.UID)
+		e.mu.Unlock()
+		return false
+	}
+	metrics.EvictionsInFlight.Inc()
+	return true
+}
+
+// reserve records pod as in-flight and consumes a rate-limit token under
+// e.mu, without touching the semaphore. It returns false if pod is already
+// being evicted or the per-minute budget is exhausted.
+func (e *evictionConcurrency) reserve(pod *v1.Pod) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.inFlight[pod.UID]; ok {
+		return false
+	}
+	if e.perMinute > 0 {
+		cutoff := time.Now().Add(-time.Minute)
+		kept := e.recent[:0]
+		for _, t := range e.recent {
+			if t.After(cutoff) {
+				kept = append(kept, t)
+			}
+		}
+		e.recent = kept
+		if len(e.recent) >= e.perMinute {
+			klog.InfoS("Eviction manager: per-node eviction rate limit reached, deferring pod", "pod", klog.KObj(pod))
+			return false
+		}
+	}
+	e.inFlight[pod.UID] = struct{}{}
+	e.recent = append(e.recent, time.Now())
+	return true
+}
+
+// finish releases the concurrency slot reserved by tryStart for pod.
+func (e *evictionConcurrency) finish(pod *v1.Pod) {
+	e.mu.Lock()
+	delete(e.inFlight, pod.UID)
+	e.mu.Unlock()
+	e.sem.Release(1)
+	metrics.EvictionsInFlight.Dec()
+}
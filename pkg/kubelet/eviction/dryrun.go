@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+)
+
+// maxTrackedDecisions bounds how many EvictionDecisions LastDecisions() keeps
+// around, so a kubelet left in dry-run mode for a long time doesn't grow this
+// slice without bound.
+const maxTrackedDecisions = 50
+
+// EvictionDecision records what the eviction manager would have done for a
+// single synchronize cycle: the signal that triggered it, the pod it would
+// have killed, and the full rank-ordered candidate list it chose from. In
+// DryRun mode these are recorded in place of an actual kill so operators can
+// validate new --eviction-soft/--eviction-hard values before they bite.
+type EvictionDecision struct {
+	Time             time.Time
+	Signal           evictionapi.Signal
+	Observed         string
+	Threshold        string
+	GracePeriodMet   bool
+	SoftEviction     bool
+	Pod              types.NamespacedName
+	RankedCandidates []types.NamespacedName
+}
+
+// recordDecision appends decision to m.lastDecisions, trimming the oldest
+// entry once maxTrackedDecisions is exceeded.
+func (m *managerImpl) recordDecision(decision EvictionDecision) {
+	m.decisionsLock.Lock()
+	defer m.decisionsLock.Unlock()
+	m.lastDecisions = append(m.lastDecisions, decision)
+	if len(m.lastDecisions) > maxTrackedDecisions {
+		m.lastDecisions = m.lastDecisions[len(m.lastDecisions)-maxTrackedDecisions:]
+	}
+}
+
+// LastDecisions returns the most recent EvictionDecisions the manager would
+// have made for signals in dry-run mode, newest last. Evictions actually
+// carried out are not included. Intended for
+// node-problem-detector or a sidecar to scrape without parsing kubelet logs.
+func (m *managerImpl) LastDecisions() []EvictionDecision {
+	m.decisionsLock.RLock()
+	defer m.decisionsLock.RUnlock()
+	out := make([]EvictionDecision, len(m.lastDecisions))
+	copy(out, m.lastDecisions)
+	return out
+}
+
+// isDryRun reports whether signal should be observed-only rather than acted
+// on, honoring both the global Config.DryRun switch and any per-signal
+// override in Config.DryRunSignals.
+func (m *managerImpl) isDryRun(signal evictionapi.Signal) bool {
+	if override, ok := m.config.DryRunSignals[signal]; ok {
+		return override
+	}
+	return m.config.DryRun
+}
+
+// candidateNames converts a ranked pod list to the NamespacedName form
+// EvictionDecision records, so the decision stays cheap to retain and to log.
+func candidateNames(pods []*v1.Pod) []types.NamespacedName {
+	names := make([]types.NamespacedName, len(pods))
+	for i, pod := range pods {
+		names[i] = types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+	}
+	return names
+}
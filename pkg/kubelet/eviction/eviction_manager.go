@@ -27,6 +27,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/tools/record"
 	v1helper "k8s.io/component-helpers/scheduling/corev1"
@@ -101,6 +102,37 @@ type managerImpl struct {
 	thresholdsLastUpdated time.Time
 	// whether can support local storage capacity isolation
 	localStorageCapacityIsolation bool
+	// psiProvider supplies cgroup v2 Pressure Stall Information observations,
+	// in addition to the absolute-capacity thresholds above. Disabled (and
+	// left nil observations are skipped) on cgroup v1 or missing PSI support.
+	psiProvider PSIProvider
+	// podCgroupReclaimer tightens a candidate pod's cgroup memory controls
+	// for a soft-reclaim attempt before falling back to killing it. Nil
+	// disables the soft-reclaim tier entirely.
+	podCgroupReclaimer PodCgroupReclaimer
+	// evictionAPIClient POSTs the policy/v1 Eviction subresource when
+	// Config.EvictionOnlyByAPI is set, so PDBs are honored the same way they
+	// are for API-initiated evictions.
+	evictionAPIClient PodEvictionAPIClient
+	// concurrency bounds how many evictPod calls may run at once and how
+	// many pods may be evicted per minute, and tracks the in-flight set so a
+	// pod is never double-evicted across synchronize ticks.
+	concurrency *evictionConcurrency
+	// decisionsLock protects lastDecisions
+	decisionsLock sync.RWMutex
+	// lastDecisions records the most recent EvictionDecisions. Only signals
+	// in dry-run mode record one; real evictions are not recorded here.
+	lastDecisions []EvictionDecision
+	// rankerLock protects rankerPlugins and rankerGraceOverrides
+	rankerLock sync.Mutex
+	// rankerPlugins caches the dialed RankerPlugin for each signal whose
+	// Config.RankerPolicies entry selects the "remote" policy, so a
+	// plugin's gRPC connection is reused across synchronize ticks.
+	rankerPlugins map[evictionapi.Signal]RankerPlugin
+	// rankerGraceOverrides holds the sanitized per-pod grace period
+	// overrides requested by the ranker plugin on its most recent Rank
+	// call, consulted by evictRankedPods/evictConcurrently.
+	rankerGraceOverrides map[types.UID]int64
 }
 
 // ensure it implements the required interface
@@ -118,6 +150,8 @@ func NewManager(
 	nodeRef *v1.ObjectReference,
 	clock clock.WithTicker,
 	localStorageCapacityIsolation bool,
+	podCgroupReclaimer PodCgroupReclaimer,
+	evictionAPIClient PodEvictionAPIClient,
 ) (Manager, lifecycle.PodAdmitHandler) {
 	manager := &managerImpl{
 		clock:                         clock,
@@ -134,6 +168,10 @@ func NewManager(
 		dedicatedImageFs:              nil,
 		thresholdNotifiers:            []ThresholdNotifier{},
 		localStorageCapacityIsolation: localStorageCapacityIsolation,
+		psiProvider:                   NewPSIProvider(),
+		podCgroupReclaimer:            podCgroupReclaimer,
+		evictionAPIClient:             evictionAPIClient,
+		concurrency:                   newEvictionConcurrency(config.MaxConcurrentEvictions, config.MaxEvictionsPerMinute),
 	}
 	return manager, manager
 }
@@ -197,6 +235,20 @@ func (m *managerImpl) Start(diskInfoProvider DiskInfoProvider, podFunc ActivePod
 			}
 		}
 	}
+	if m.psiProvider != nil && m.psiProvider.Available() {
+		for _, threshold := range m.config.Thresholds {
+			if _, isPSISignal := psiSignalFiles[threshold.Signal]; !isPSISignal {
+				continue
+			}
+			notifier, err := NewPSIThresholdNotifier(threshold, m.config.PodCgroupRoot, thresholdHandler)
+			if err != nil {
+				klog.InfoS("Eviction manager: failed to create PSI threshold notifier", "err", err)
+				continue
+			}
+			go notifier.Start()
+			m.thresholdNotifiers = append(m.thresholdNotifiers, notifier)
+		}
+	}
 	// start the eviction manager monitoring
 	go func() {
 		for {
@@ -253,6 +305,7 @@ func (m *managerImpl) synchronize(diskInfoProvider DiskInfoProvider, podFunc Act
 		m.dedicatedImageFs = &hasImageFs
 		m.signalToRankFunc = buildSignalToRankFunc(hasImageFs)
 		m.signalToNodeReclaimFuncs = buildSignalToNodeReclaimFuncs(m.imageGC, m.containerGC, hasImageFs)
+		addPIDPressureSignals(m.signalToRankFunc, m.signalToNodeReclaimFuncs, m.containerGC)
 	}
 
 	activePods := podFunc()
@@ -274,6 +327,7 @@ func (m *managerImpl) synchronize(diskInfoProvider DiskInfoProvider, podFunc Act
 
 	// make observations and get a function to derive pod usage stats relative to those observations.
 	observations, statsFunc := makeSignalObservations(summary)
+	addPSIObservations(observations, m.psiProvider)
 	debugLogObservations("observations", observations)
 
 	// determine the set of thresholds met independent of grace period
@@ -358,7 +412,7 @@ func (m *managerImpl) synchronize(diskInfoProvider DiskInfoProvider, podFunc Act
 	klog.InfoS("Eviction manager: must evict pod(s) to reclaim", "resourceName", resourceToReclaim)
 
 	// rank the pods for eviction
-	rank, ok := m.signalToRankFunc[thresholdToReclaim.Signal]
+	legacyRank, ok := m.signalToRankFunc[thresholdToReclaim.Signal]
 	if !ok {
 		klog.ErrorS(nil, "Eviction manager: no ranking function for signal", "threshold", thresholdToReclaim.Signal)
 		return nil
@@ -370,8 +424,10 @@ func (m *managerImpl) synchronize(diskInfoProvider DiskInfoProvider, podFunc Act
 		return nil
 	}
 
-	// rank the running pods for eviction for the specified resource
-	rank(activePods, statsFunc)
+	// rank the running pods for eviction for the specified resource, using
+	// whichever PodRanker policy is configured for this signal.
+	ranker := m.resolveRanker(thresholdToReclaim, legacyRank)
+	ranker.Rank(activePods, statsFunc)
 
 	klog.InfoS("Eviction manager: pods ranked for eviction", "pods", klog.KObjSlice(activePods))
 
@@ -383,30 +439,30 @@ func (m *managerImpl) synchronize(diskInfoProvider DiskInfoProvider, podFunc Act
 		}
 	}
 
-	// we kill at most a single pod during each eviction interval
-	for i := range activePods {
-		pod := activePods[i]
-		gracePeriodOverride := int64(0)
-		if !isHardEvictionThreshold(thresholdToReclaim) {
-			gracePeriodOverride = m.config.MaxPodGracePeriodSeconds
-		}
-		message, annotations := evictionMessage(resourceToReclaim, pod, statsFunc, thresholds, observations)
-		var condition *v1.PodCondition
-		if utilfeature.DefaultFeatureGate.Enabled(features.PodDisruptionConditions) {
-			condition = &v1.PodCondition{
-				Type:    v1.DisruptionTarget,
-				Status:  v1.ConditionTrue,
-				Reason:  v1.PodReasonTerminationByKubelet,
-				Message: message,
-			}
-		}
-		if m.evictPod(pod, gracePeriodOverride, message, annotations, condition) {
-			metrics.Evictions.WithLabelValues(string(thresholdToReclaim.Signal)).Inc()
-			return []*v1.Pod{pod}
+	if m.isDryRun(thresholdToReclaim.Signal) {
+		dryRunCandidates := m.applyBreakGlassOrdering(activePods, thresholdToReclaim)
+		if len(dryRunCandidates) == 0 {
+			// Every active pod is break-glass-protected and the threshold is
+			// soft, so applyBreakGlassOrdering dropped them all: there's no
+			// candidate this cycle, not even a hypothetical one to report.
+			klog.InfoS("Eviction manager: dry run, no eviction candidates after break-glass ordering", "signal", thresholdToReclaim.Signal)
+			return nil
 		}
+		topCandidate := dryRunCandidates[0]
+		m.recordDecision(EvictionDecision{
+			Time:             m.clock.Now(),
+			Signal:           thresholdToReclaim.Signal,
+			Threshold:        fmt.Sprintf("%v", thresholdToReclaim.Value),
+			GracePeriodMet:   true,
+			SoftEviction:     !isHardEvictionThreshold(thresholdToReclaim),
+			Pod:              types.NamespacedName{Namespace: topCandidate.Namespace, Name: topCandidate.Name},
+			RankedCandidates: candidateNames(dryRunCandidates),
+		})
+		klog.InfoS("Eviction manager: dry run, would have evicted pod", "pod", klog.KObj(topCandidate), "signal", thresholdToReclaim.Signal)
+		return nil
 	}
-	klog.InfoS("Eviction manager: unable to evict any pods from the node")
-	return nil
+
+	return m.evictRankedPods(ctx, activePods, thresholdToReclaim, resourceToReclaim, statsFunc, observations, thresholds)
 }
 
 func (m *managerImpl) waitForPodsCleanup(podCleanedUpFunc PodCleanedUpFunc, pods []*v1.Pod) {
@@ -501,6 +557,26 @@ func (m *managerImpl) localStorageEviction(pods []*v1.Pod, statsFunc statsFunc)
 	return evicted
 }
 
+// evictOrRecordLocalStorage gates a local-storage eviction the same way
+// synchronize's threshold path does: when signal is in dry-run, it records
+// an EvictionDecision instead of calling evictPod, so DryRun's "walk the
+// full decision pipeline... without calling killPodFunc" promise also holds
+// for emptyDir/ephemeral-storage overage, not just resource thresholds.
+func (m *managerImpl) evictOrRecordLocalStorage(pod *v1.Pod, signal evictionapi.Signal, message string) bool {
+	if m.isDryRun(signal) {
+		m.recordDecision(EvictionDecision{
+			Time:           m.clock.Now(),
+			Signal:         signal,
+			Threshold:      message,
+			GracePeriodMet: true,
+			Pod:            types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name},
+		})
+		klog.InfoS("Eviction manager: dry run, would have evicted pod", "pod", klog.KObj(pod), "signal", signal)
+		return true
+	}
+	return m.evictPod(pod, 0, message, nil, nil)
+}
+
 func (m *managerImpl) emptyDirLimitEviction(podStats statsapi.PodStats, pod *v1.Pod) bool {
 	podVolumeUsed := make(map[string]*resource.Quantity)
 	for _, volume := range podStats.VolumeStats {
@@ -514,7 +590,7 @@ func (m *managerImpl) emptyDirLimitEviction(podStats statsapi.PodStats, pod *v1.
 			used := podVolumeUsed[pod.Spec.Volumes[i].Name]
 			if used != nil && size != nil && size.Sign() == 1 && used.Cmp(*size) > 0 {
 				// the emptyDir usage exceeds the size limit, evict the pod
-				if m.evictPod(pod, 0, fmt.Sprintf(emptyDirMessageFmt, pod.Spec.Volumes[i].Name, size.String()), nil, nil) {
+				if m.evictOrRecordLocalStorage(pod, evictionapi.Signal(signalEmptyDirFsLimit), fmt.Sprintf(emptyDirMessageFmt, pod.Spec.Volumes[i].Name, size.String())) {
 					metrics.Evictions.WithLabelValues(signalEmptyDirFsLimit).Inc()
 					return true
 				}
@@ -543,7 +619,7 @@ func (m *managerImpl) podEphemeralStorageLimitEviction(podStats statsapi.PodStat
 	if podEphemeralStorageTotalUsage.Cmp(podEphemeralStorageLimit) > 0 {
 		// the total usage of pod exceeds the total size limit of containers, evict the pod
 		message := fmt.Sprintf(podEphemeralStorageMessageFmt, podEphemeralStorageLimit.String())
-		if m.evictPod(pod, 0, message, nil, nil) {
+		if m.evictOrRecordLocalStorage(pod, evictionapi.Signal(signalEphemeralPodFsLimit), message) {
 			metrics.Evictions.WithLabelValues(signalEphemeralPodFsLimit).Inc()
 			return true
 		}
@@ -570,7 +646,7 @@ func (m *managerImpl) containerEphemeralStorageLimitEviction(podStats statsapi.P
 		// 比较该Pod下的每一个容器的实际临时存储已用值和Limit比较，有一个容器不满足则返回true
 		if ephemeralStorageThreshold, ok := thresholdsMap[containerStat.Name]; ok {
 			if ephemeralStorageThreshold.Cmp(*containerUsed) < 0 {
-				if m.evictPod(pod, 0, fmt.Sprintf(containerEphemeralStorageMessageFmt, containerStat.Name, ephemeralStorageThreshold.String()), nil, nil) {
+				if m.evictOrRecordLocalStorage(pod, evictionapi.Signal(signalEphemeralContainerFsLimit), fmt.Sprintf(containerEphemeralStorageMessageFmt, containerStat.Name, ephemeralStorageThreshold.String())) {
 					metrics.Evictions.WithLabelValues(signalEphemeralContainerFsLimit).Inc()
 					return true
 				}
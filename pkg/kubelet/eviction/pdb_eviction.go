@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+)
+
+// defaultEvictionAPIRetries bounds how many times evictPodRespectingPDB
+// retries a PDB-blocked (TooManyRequests) eviction before giving up on this
+// candidate and letting the caller move to the next one, so a pressure
+// signal is never blocked forever behind a single stubborn PDB.
+const defaultEvictionAPIRetries = 3
+
+// PodEvictionAPIClient POSTs a policy/v1 Eviction subresource for a pod,
+// mirroring the same "only-evict-by-api" pattern used by QoS managers and by
+// API-initiated evictions, so PodDisruptionBudgets are honored before the
+// kubelet kills a pod's containers outright.
+type PodEvictionAPIClient interface {
+	Evict(ctx context.Context, pod *v1.Pod, gracePeriodSeconds int64) error
+}
+
+// evictPodRespectingPDB evicts pod, optionally gating the kill behind a
+// successful policy/v1 Eviction API call when Config.EvictionOnlyByAPI is
+// set. A TooManyRequests response (a PDB violation) is retried a bounded
+// number of times with backoff and then reported as "not evicted" so the
+// caller tries the next ranked candidate instead of blocking on this pod
+// forever.
+func (m *managerImpl) evictPodRespectingPDB(ctx context.Context, pod *v1.Pod, gracePeriodOverride int64, evictMsg string, annotations map[string]string, condition *v1.PodCondition) bool {
+	if !m.config.EvictionOnlyByAPI || m.evictionAPIClient == nil {
+		return m.evictPod(pod, gracePeriodOverride, evictMsg, annotations, condition)
+	}
+
+	backoff := 100 * time.Millisecond
+	for attempt := 0; attempt < defaultEvictionAPIRetries; attempt++ {
+		err := m.evictionAPIClient.Evict(ctx, pod, gracePeriodOverride)
+		if err == nil {
+			return m.evictPod(pod, gracePeriodOverride, evictMsg, annotations, condition)
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			klog.InfoS("Eviction manager: eviction API call failed", "pod", klog.KObj(pod), "err", err)
+			return false
+		}
+		klog.InfoS("Eviction manager: eviction API call blocked by PodDisruptionBudget, backing off", "pod", klog.KObj(pod), "attempt", attempt)
+		m.clock.Sleep(backoff)
+		backoff *= 2
+	}
+	klog.InfoS("Eviction manager: pod still protected by a PodDisruptionBudget after retries, trying next candidate", "pod", klog.KObj(pod))
+	return false
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+)
+
+// addPIDPressureSignals wires a process-count ranker and a container-GC
+// reclaim step into rankFuncs/reclaimFuncs for SignalPIDAvailable and
+// SignalAllocatablePIDAvailable, which buildSignalToRankFunc and
+// buildSignalToNodeReclaimFuncs don't cover on their own.
+func addPIDPressureSignals(rankFuncs map[evictionapi.Signal]rankFunc, reclaimFuncs map[evictionapi.Signal]nodeReclaimFuncs, containerGC ContainerGC) {
+	for _, signal := range []evictionapi.Signal{evictionapi.SignalPIDAvailable, evictionapi.SignalAllocatablePIDAvailable} {
+		rankFuncs[signal] = rankPIDPressure
+		reclaimFuncs[signal] = nodeReclaimFuncs{containerGCReclaim(containerGC)}
+	}
+}
+
+// rankPIDPressure orders pods by their total process count, highest first,
+// breaking ties by priority and then by memory usage; QoS class is not
+// compared on its own. A pod spawning far more processes than its peers (a fork bomb, a runaway
+// thread pool) is usually the best candidate to free PIDs.
+func rankPIDPressure(pods []*v1.Pod, stats statsFunc) {
+	orderedBy(processCount(stats), priority, memory(stats)).Sort(pods)
+}
+
+// processCount returns a cmpFunc that orders pods by descending total
+// process count, summed across the pod's containers.
+func processCount(stats statsFunc) cmpFunc {
+	return func(p1, p2 *v1.Pod) int {
+		p1Count, p1Found := podProcessCount(p1, stats)
+		p2Count, p2Found := podProcessCount(p2, stats)
+		if !p1Found || !p2Found {
+			return 0
+		}
+		// higher process count ranks first (descending sort), so swap operand order.
+		return int(p2Count - p1Count)
+	}
+}
+
+// podProcessCount sums ProcessCount across the pod's containers. ok is false
+// when stats couldn't be retrieved for the pod at all.
+func podProcessCount(pod *v1.Pod, stats statsFunc) (total int64, ok bool) {
+	podStats, found := stats(pod)
+	if !found {
+		return 0, false
+	}
+	for _, container := range podStats.Containers {
+		if container.ProcessCount != nil {
+			total += int64(*container.ProcessCount)
+		}
+	}
+	return total, true
+}
+
+// containerGCReclaim wraps ContainerGC.DeleteAllUnusedContainers as a
+// nodeReclaimFunc, so PID pressure tries freeing PIDs held by already-exited
+// containers before evicting a running pod.
+func containerGCReclaim(containerGC ContainerGC) nodeReclaimFunc {
+	return func(_ context.Context) error {
+		return containerGC.DeleteAllUnusedContainers()
+	}
+}
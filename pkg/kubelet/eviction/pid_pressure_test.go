@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	statsapi "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func TestPodProcessCount(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod"}}
+
+	testCases := map[string]struct {
+		stats     statsFunc
+		wantTotal int64
+		wantFound bool
+	}{
+		"no stats found": {
+			stats: func(*v1.Pod) (statsapi.PodStats, bool) {
+				return statsapi.PodStats{}, false
+			},
+			wantTotal: 0,
+			wantFound: false,
+		},
+		"single container": {
+			stats: func(*v1.Pod) (statsapi.PodStats, bool) {
+				return statsapi.PodStats{
+					Containers: []statsapi.ContainerStats{
+						{Name: "c1", ProcessCount: uint64Ptr(12)},
+					},
+				}, true
+			},
+			wantTotal: 12,
+			wantFound: true,
+		},
+		"multiple containers summed": {
+			stats: func(*v1.Pod) (statsapi.PodStats, bool) {
+				return statsapi.PodStats{
+					Containers: []statsapi.ContainerStats{
+						{Name: "c1", ProcessCount: uint64Ptr(12)},
+						{Name: "c2", ProcessCount: uint64Ptr(30)},
+					},
+				}, true
+			},
+			wantTotal: 42,
+			wantFound: true,
+		},
+		"container with nil ProcessCount is skipped": {
+			stats: func(*v1.Pod) (statsapi.PodStats, bool) {
+				return statsapi.PodStats{
+					Containers: []statsapi.ContainerStats{
+						{Name: "c1", ProcessCount: nil},
+						{Name: "c2", ProcessCount: uint64Ptr(5)},
+					},
+				}, true
+			},
+			wantTotal: 5,
+			wantFound: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			total, found := podProcessCount(pod, tc.stats)
+			if found != tc.wantFound {
+				t.Errorf("podProcessCount() found = %v, want %v", found, tc.wantFound)
+			}
+			if total != tc.wantTotal {
+				t.Errorf("podProcessCount() total = %d, want %d", total, tc.wantTotal)
+			}
+		})
+	}
+}
+
+func TestProcessCountOrdersDescending(t *testing.T) {
+	podA := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "few-processes"}}
+	podB := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "many-processes"}}
+
+	stats := func(pod *v1.Pod) (statsapi.PodStats, bool) {
+		switch pod.Name {
+		case "few-processes":
+			return statsapi.PodStats{Containers: []statsapi.ContainerStats{{Name: "c", ProcessCount: uint64Ptr(3)}}}, true
+		case "many-processes":
+			return statsapi.PodStats{Containers: []statsapi.ContainerStats{{Name: "c", ProcessCount: uint64Ptr(300)}}}, true
+		default:
+			return statsapi.PodStats{}, false
+		}
+	}
+
+	cmp := processCount(stats)
+	if result := cmp(podA, podB); result <= 0 {
+		t.Errorf("processCount(few, many) = %d, want > 0 so the higher process count sorts first", result)
+	}
+	if result := cmp(podB, podA); result >= 0 {
+		t.Errorf("processCount(many, few) = %d, want < 0 so the higher process count sorts first", result)
+	}
+}
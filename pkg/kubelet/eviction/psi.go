@@ -0,0 +1,280 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	statsapi "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+)
+
+// PSI signals report the percentage of time (avg10/avg60/avg300, over the
+// matching window in seconds) that tasks were stalled waiting on a resource,
+// per Linux's Pressure Stall Information. Thresholds like
+// "memory.pressure.avg10>20" read as "stalled >20% over 10s".
+const (
+	SignalCPUPressureAvg10    evictionapi.Signal = "cpu.pressure.avg10"
+	SignalMemoryPressureAvg10 evictionapi.Signal = "memory.pressure.avg10"
+	SignalIOPressureAvg10     evictionapi.Signal = "io.pressure.avg10"
+)
+
+// psiCapacity is the scale PSI signals are reported against: observations are
+// recorded as "percent stalled" out of 100, not an absolute resource quantity.
+var psiCapacity = resource.MustParse("100")
+
+// psiSignalFiles maps each PSI signal to the /proc/pressure file it's read
+// from. A per-cgroup PSIProvider reads the equivalent file under the pod or
+// node's cgroup instead (e.g. memory.pressure under cgroup v2).
+var psiSignalFiles = map[evictionapi.Signal]string{
+	SignalCPUPressureAvg10:    "/proc/pressure/cpu",
+	SignalMemoryPressureAvg10: "/proc/pressure/memory",
+	SignalIOPressureAvg10:     "/proc/pressure/io",
+}
+
+// psiCgroupPressureFiles maps each PSI signal to the file name cgroup v2
+// exposes it under inside a cgroup directory (as opposed to psiSignalFiles'
+// node-wide /proc/pressure/* paths).
+var psiCgroupPressureFiles = map[evictionapi.Signal]string{
+	SignalCPUPressureAvg10:    "cpu.pressure",
+	SignalMemoryPressureAvg10: "memory.pressure",
+	SignalIOPressureAvg10:     "io.pressure",
+}
+
+// PSIProvider reads PSI pressure files and reports them as
+// signalObservations, one per PSI signal. The /proc/pressure-backed provider
+// reads only the "some" avg10 field; the "full" line and the avg60/avg300
+// fields are not read. Available
+// reports false on cgroup v1 or when PSI is otherwise unsupported, so Start
+// can disable these signals cleanly instead of failing.
+type PSIProvider interface {
+	Available() bool
+	Observations() (map[evictionapi.Signal]signalObservation, error)
+}
+
+// procfsPSIProvider reads PSI from the node-wide /proc/pressure files.
+type procfsPSIProvider struct{}
+
+// NewPSIProvider returns the default PSIProvider, backed by /proc/pressure.
+func NewPSIProvider() PSIProvider {
+	return &procfsPSIProvider{}
+}
+
+func (p *procfsPSIProvider) Available() bool {
+	_, err := os.Stat("/proc/pressure")
+	return err == nil
+}
+
+func (p *procfsPSIProvider) Observations() (map[evictionapi.Signal]signalObservation, error) {
+	observations := make(map[evictionapi.Signal]signalObservation, len(psiSignalFiles))
+	for signal, path := range psiSignalFiles {
+		avg10, err := readSomeAvg10(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading PSI signal %s from %s: %w", signal, path, err)
+		}
+		observations[signal] = signalObservation{
+			time:     metav1.Now(),
+			capacity: &psiCapacity,
+			// available is headroom, not avg10 itself: thresholdsMet and every
+			// other signal in this package test "available < threshold.Value",
+			// so storing avg10 directly would fire a "pressure.avg10>20"
+			// threshold when pressure is LOW. Storing (100 - avg10) makes the
+			// shared "available < threshold.Value" check reduce to the
+			// intended "avg10 > (100 - threshold.Value)".
+			available: resource.NewQuantity(psiCapacity.Value()-int64(avg10), resource.DecimalSI),
+		}
+	}
+	return observations, nil
+}
+
+// readSomeAvg10 parses the "some avg10=<pct> avg60=<pct> avg300=<pct> total=<us>"
+// line out of a /proc/pressure/{cpu,memory,io} (or per-cgroup pressure) file
+// and returns avg10 as a whole-number percentage.
+func readSomeAvg10(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "some ") {
+			continue
+		}
+		for _, field := range strings.Fields(line)[1:] {
+			if !strings.HasPrefix(field, "avg10=") {
+				continue
+			}
+			val, err := strconv.ParseFloat(strings.TrimPrefix(field, "avg10="), 64)
+			if err != nil {
+				return 0, err
+			}
+			return int(val), nil
+		}
+	}
+	return 0, fmt.Errorf("no 'some' line with avg10 found in %s", path)
+}
+
+// addPSIObservations merges provider's readings into observations, the way
+// makeSignalObservations populates every other signal, skipping silently when
+// provider isn't available (cgroup v1 or PSI disabled) so Start can proceed
+// without these signals.
+func addPSIObservations(observations signalObservations, provider PSIProvider) {
+	if provider == nil || !provider.Available() {
+		return
+	}
+	psiObservations, err := provider.Observations()
+	if err != nil {
+		return
+	}
+	for signal, obs := range psiObservations {
+		observations[signal] = obs
+	}
+}
+
+// psiAvg10WindowMicros is the averaging window, in microseconds, the avg10
+// threshold signals (the only ones this notifier arms a kernel trigger for)
+// are computed over.
+const psiAvg10WindowMicros = int64(10 * time.Second / time.Microsecond)
+
+// psiThresholdNotifier wakes synchronize promptly on a sustained PSI stall.
+// It arms the kernel's PSI monitor by writing "some <threshold_us> <window_us>"
+// into the cgroup's pressure file per the cgroup v2 documentation, then
+// blocks in poll(2) on that file descriptor for POLLPRI, the PSI analogue of
+// MemoryThresholdNotifier's memcg eventfd notification — pressure files only
+// support poll/select, not epoll.
+type psiThresholdNotifier struct {
+	threshold evictionapi.Threshold
+	path      string
+	handler   func(message string)
+	stop      chan struct{}
+}
+
+// NewPSIThresholdNotifier returns a ThresholdNotifier for threshold, which
+// must be one of the PSI signals in psiSignalFiles. cgroupPressurePath is the
+// cgroup v2 directory (e.g. Config.PodCgroupRoot) whose
+// cpu.pressure/memory.pressure/io.pressure file backs the trigger.
+func NewPSIThresholdNotifier(threshold evictionapi.Threshold, cgroupPressurePath string, handler func(message string)) (ThresholdNotifier, error) {
+	file, ok := psiCgroupPressureFiles[threshold.Signal]
+	if !ok {
+		return nil, fmt.Errorf("unsupported PSI signal: %s", threshold.Signal)
+	}
+	return &psiThresholdNotifier{
+		threshold: threshold,
+		path:      filepath.Join(cgroupPressurePath, file),
+		handler:   handler,
+		stop:      make(chan struct{}),
+	}, nil
+}
+
+// Start arms the kernel PSI trigger and loops in poll(2), calling the handler
+// each time the trigger fires, until n.stop is closed or poll reports an
+// error or POLLERR (e.g. the cgroup was removed), at which point it returns.
+// If the pressure file can't be opened or the trigger can't be armed, it
+// falls back to polling on a timer instead.
+func (n *psiThresholdNotifier) Start() {
+	fd, err := os.OpenFile(n.path, os.O_RDWR, 0)
+	if err != nil {
+		klog.InfoS("Eviction manager: failed to open PSI pressure file, falling back to timed polling", "path", n.path, "err", err)
+		n.pollOnTimer()
+		return
+	}
+	defer fd.Close()
+
+	triggerUs := int64(0)
+	if n.threshold.Value.Quantity != nil {
+		triggerUs = n.threshold.Value.Quantity.Value() * psiAvg10WindowMicros / 100
+	}
+	if _, err := fd.WriteString(fmt.Sprintf("some %d %d", triggerUs, psiAvg10WindowMicros)); err != nil {
+		klog.InfoS("Eviction manager: failed to arm PSI trigger, falling back to timed polling", "path", n.path, "err", err)
+		n.pollOnTimer()
+		return
+	}
+
+	pollFds := []unix.PollFd{{Fd: int32(fd.Fd()), Events: unix.POLLPRI}}
+	for {
+		select {
+		case <-n.stop:
+			return
+		default:
+		}
+		nReady, err := unix.Poll(pollFds, 1000)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			klog.InfoS("Eviction manager: poll on PSI pressure file failed, stopping notifier", "path", n.path, "err", err)
+			return
+		}
+		if nReady == 0 {
+			continue
+		}
+		if pollFds[0].Revents&unix.POLLERR != 0 {
+			klog.InfoS("Eviction manager: PSI pressure file reported POLLERR, cgroup may have been removed", "path", n.path)
+			return
+		}
+		if pollFds[0].Revents&unix.POLLPRI != 0 {
+			n.handler(fmt.Sprintf("PSI threshold met for %s", n.threshold.Signal))
+		}
+	}
+}
+
+// pollOnTimer is the fallback used when the kernel PSI trigger can't be
+// armed: it re-reads avg10 off n.path every 5s until Stop is called.
+func (n *psiThresholdNotifier) pollOnTimer() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-ticker.C:
+			avg10, err := readSomeAvg10(n.path)
+			if err != nil {
+				continue
+			}
+			if n.threshold.Value.Quantity != nil && int64(avg10) >= n.threshold.Value.Quantity.Value() {
+				n.handler(fmt.Sprintf("PSI threshold met for %s: avg10=%d", n.threshold.Signal, avg10))
+			}
+		}
+	}
+}
+
+// Stop ends Start's poll loop (or pollOnTimer fallback).
+func (n *psiThresholdNotifier) Stop() {
+	close(n.stop)
+}
+
+func (n *psiThresholdNotifier) UpdateThreshold(*statsapi.Summary) error {
+	return nil
+}
+
+func (n *psiThresholdNotifier) Description() string {
+	return fmt.Sprintf("psi threshold notifier for %s", n.threshold.Signal)
+}
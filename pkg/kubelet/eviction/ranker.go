@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+)
+
+// evictionCostAnnotation lets a pod influence its own eviction order, similar
+// to how cluster-autoscaler honors safe-to-evict: a higher cost makes a pod
+// less attractive to evict relative to same-priority peers.
+const evictionCostAnnotation = "eviction.k8s.io/cost"
+
+// defaultRankerPolicy is used for any signal not named in
+// Config.RankerPolicies, and preserves today's behavior.
+const defaultRankerPolicy = "default"
+
+// PodRanker orders pods (in place) from most to least preferred for eviction
+// for a given signal. Built-in policies are registered under "default",
+// "priority-first" and "cost-aware"; operators select one per signal via
+// Config.RankerPolicies.
+type PodRanker interface {
+	Rank(pods []*v1.Pod, stats statsFunc)
+}
+
+// rankFuncRanker adapts the legacy rankFunc signature (used by the signal-specific
+// QoS->PriorityClass->usage orderings built in helpers.go) to PodRanker.
+type rankFuncRanker struct {
+	rank rankFunc
+}
+
+func (r rankFuncRanker) Rank(pods []*v1.Pod, stats statsFunc) {
+	r.rank(pods, stats)
+}
+
+// priorityFirstRanker sorts strictly by pod.Spec.Priority, ignoring QoS —
+// for clusters where priority class alone should decide eviction order.
+type priorityFirstRanker struct{}
+
+func (priorityFirstRanker) Rank(pods []*v1.Pod, _ statsFunc) {
+	sort.Slice(pods, func(i, j int) bool {
+		return podPriority(pods[i]) < podPriority(pods[j])
+	})
+}
+
+// costAwareRanker combines priority with an annotation-configurable eviction
+// cost: among pods of equal priority, the one with the lowest cost is
+// evicted first.
+type costAwareRanker struct{}
+
+func (costAwareRanker) Rank(pods []*v1.Pod, _ statsFunc) {
+	sort.Slice(pods, func(i, j int) bool {
+		pi, pj := podPriority(pods[i]), podPriority(pods[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return podEvictionCost(pods[i]) < podEvictionCost(pods[j])
+	})
+}
+
+func podPriority(pod *v1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
+
+// podEvictionCost reads evictionCostAnnotation off pod, defaulting to 0 (no
+// preference) when absent or unparsable.
+func podEvictionCost(pod *v1.Pod) int64 {
+	v, ok := pod.Annotations[evictionCostAnnotation]
+	if !ok {
+		return 0
+	}
+	cost, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return cost
+}
+
+// rankerRegistry holds the built-in, named PodRanker policies. Signal-specific
+// default rankers are registered per-instance in managerImpl.resolveRanker,
+// since they close over the signal's legacy rankFunc. Guarded by mu since
+// RegisterRanker may run concurrently with the eviction manager's sync loop
+// calling resolveRanker.
+var rankerRegistry = struct {
+	mu     sync.RWMutex
+	byName map[string]PodRanker
+}{byName: map[string]PodRanker{
+	"priority-first": priorityFirstRanker{},
+	"cost-aware":     costAwareRanker{},
+}}
+
+// RegisterRanker registers a named PodRanker policy for later selection via
+// Config.RankerPolicies. Intended for callers that want to compose a policy
+// not shipped as a built-in.
+func RegisterRanker(name string, ranker PodRanker) {
+	rankerRegistry.mu.Lock()
+	defer rankerRegistry.mu.Unlock()
+	rankerRegistry.byName[name] = ranker
+}
+
+// lookupRanker returns the PodRanker registered under name, or nil if none is
+// registered.
+func lookupRanker(name string) PodRanker {
+	rankerRegistry.mu.RLock()
+	defer rankerRegistry.mu.RUnlock()
+	return rankerRegistry.byName[name]
+}
+
+// resolveRanker returns the PodRanker configured for threshold.Signal via
+// Config.RankerPolicies, falling back to the legacy per-signal rankFunc
+// (wrapped as defaultRankerPolicy) when unset or unrecognized.
+func (m *managerImpl) resolveRanker(threshold evictionapi.Threshold, legacy rankFunc) PodRanker {
+	policy := defaultRankerPolicy
+	if p, ok := m.config.RankerPolicies[threshold.Signal]; ok {
+		policy = p
+	}
+	if policy == defaultRankerPolicy {
+		return rankFuncRanker{rank: legacy}
+	}
+	if policy == remoteRankerPolicy {
+		return &pluginRanker{
+			plugin:    m.resolveRankerPlugin(threshold.Signal, legacy),
+			threshold: threshold,
+			fallback:  legacy,
+			manager:   m,
+		}
+	}
+	if ranker := lookupRanker(policy); ranker != nil {
+		return ranker
+	}
+	return rankFuncRanker{rank: legacy}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	statsapi "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+	"k8s.io/klog/v2"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+	rankerapi "k8s.io/kubernetes/pkg/kubelet/eviction/rankerapi/v1"
+	kubelettypes "k8s.io/kubernetes/pkg/kubelet/types"
+)
+
+// remoteRankerPolicy is the Config.RankerPolicies value that selects
+// newRemoteRankerPlugin, the same way "priority-first" and "cost-aware"
+// select their PodRanker built-ins.
+const remoteRankerPolicy = "remote"
+
+// defaultRemoteRankerTimeout bounds a single Rank RPC, so a wedged or slow
+// plugin can never stall a pressure-relief cycle; on timeout or any other
+// error the caller falls back to the legacy rankFunc ordering.
+const defaultRemoteRankerTimeout = 5 * time.Second
+
+// RankerPlugin is the out-of-tree extension point for eviction-candidate
+// ordering, modeled on the CRI/CSI plugin pattern: the kubelet dials a gRPC
+// socket and asks the plugin to order this cycle's candidates, but it alone
+// never decides who gets evicted. pluginRanker still enforces the mandatory
+// safety rails (never promote a critical or break-glass-protected pod, cap
+// any requested grace period override) before acting on the plugin's answer.
+type RankerPlugin interface {
+	// Rank returns, for the given threshold and candidate pods (with their
+	// current stats available via stats), the candidates' UIDs in
+	// most-to-least-preferred eviction order, plus an optional per-pod grace
+	// period override for any UID that should deviate from the threshold's
+	// default grace period.
+	Rank(ctx context.Context, pods []*v1.Pod, stats statsFunc, threshold evictionapi.Threshold) (order []types.UID, graceOverrides map[types.UID]int64, err error)
+}
+
+// defaultRankerPlugin reproduces today's built-in QoS -> PriorityClass ->
+// usage ordering as a RankerPlugin, so selecting "remote" for one signal and
+// leaving another on its default doesn't require two different code paths
+// at the call site.
+type defaultRankerPlugin struct {
+	legacy rankFunc
+}
+
+func (d defaultRankerPlugin) Rank(_ context.Context, pods []*v1.Pod, stats statsFunc, _ evictionapi.Threshold) ([]types.UID, map[types.UID]int64, error) {
+	ranked := make([]*v1.Pod, len(pods))
+	copy(ranked, pods)
+	d.legacy(ranked, stats)
+	order := make([]types.UID, 0, len(ranked))
+	for _, pod := range ranked {
+		order = append(order, pod.UID)
+	}
+	return order, nil, nil
+}
+
+// remoteRankerPlugin calls out to an external gRPC ranking service over a
+// unix socket, the same deployment shape as a CRI or CSI driver, so cluster
+// operators can swap in custom eviction policy without a kubelet rebuild.
+type remoteRankerPlugin struct {
+	client  rankerapi.RankerClient
+	timeout time.Duration
+}
+
+// newRemoteRankerPlugin dials addr (a unix socket path, e.g.
+// "unix:///var/lib/kubelet/plugins/eviction-ranker.sock") and returns a
+// RankerPlugin backed by it. Dialing blocks until the socket accepts a
+// connection or rankerapi.Dial's timeout expires; a plugin that isn't up
+// yet fails the dial, and resolveRankerPlugin then uses the default
+// ordering without caching, so the next cycle dials again.
+func newRemoteRankerPlugin(addr string) (RankerPlugin, error) {
+	conn, err := rankerapi.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteRankerPlugin{
+		client:  rankerapi.NewRankerClient(conn),
+		timeout: defaultRemoteRankerTimeout,
+	}, nil
+}
+
+func (r *remoteRankerPlugin) Rank(ctx context.Context, pods []*v1.Pod, stats statsFunc, threshold evictionapi.Threshold) ([]types.UID, map[types.UID]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	req := &rankerapi.RankRequest{
+		Signal:    string(threshold.Signal),
+		Hard:      isHardEvictionThreshold(threshold),
+		Threshold: threshold.Value.Quantity.String(),
+	}
+	for _, pod := range pods {
+		candidate := &rankerapi.Candidate{
+			Uid:       string(pod.UID),
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+		}
+		if podStats, ok := stats(pod); ok {
+			candidate.UsageBytes = podStatsUsageBytes(podStats)
+		}
+		req.Candidates = append(req.Candidates, candidate)
+	}
+
+	resp, err := r.client.Rank(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order := make([]types.UID, 0, len(resp.OrderedUids))
+	for _, uid := range resp.OrderedUids {
+		order = append(order, types.UID(uid))
+	}
+	var graceOverrides map[types.UID]int64
+	if len(resp.GracePeriodOverrideSeconds) > 0 {
+		graceOverrides = make(map[types.UID]int64, len(resp.GracePeriodOverrideSeconds))
+		for uid, seconds := range resp.GracePeriodOverrideSeconds {
+			graceOverrides[types.UID(uid)] = seconds
+		}
+	}
+	return order, graceOverrides, nil
+}
+
+// pluginRanker adapts a RankerPlugin to PodRanker so it can be selected
+// through the same Config.RankerPolicies path as the in-process rankers,
+// applying the mandatory safety rails to whatever order the plugin returns
+// before evictRankedPods ever sees it.
+type pluginRanker struct {
+	plugin    RankerPlugin
+	threshold evictionapi.Threshold
+	fallback  rankFunc
+	manager   *managerImpl
+}
+
+func (p *pluginRanker) Rank(pods []*v1.Pod, stats statsFunc) {
+	order, graceOverrides, err := p.plugin.Rank(context.Background(), pods, stats, p.threshold)
+	if err != nil {
+		klog.InfoS("Eviction manager: ranker plugin call failed, falling back to the default ordering", "signal", p.threshold.Signal, "err", err)
+		p.fallback(pods, stats)
+		p.manager.setRankerGraceOverrides(nil)
+		return
+	}
+	// A plugin is never allowed to move a static, mirror or critical pod, or
+	// a pod carrying the break-glass annotation, ahead of an otherwise
+	// evictable one: drop any such UID from the order it returned and let
+	// evictPod's own critical-pod check and applyBreakGlassOrdering keep
+	// enforcing that rule downstream, exactly as they do for every other
+	// PodRanker.
+	order = dropProtectedUIDs(pods, order)
+	applyRankerPluginOrder(pods, order)
+	p.manager.setRankerGraceOverrides(graceOverrides)
+}
+
+// dropProtectedUIDs filters a plugin-returned order down to the UIDs of pods
+// that aren't critical or break-glass-protected, preserving relative order.
+func dropProtectedUIDs(pods []*v1.Pod, order []types.UID) []types.UID {
+	protected := make(map[types.UID]bool)
+	for _, pod := range pods {
+		if kubelettypes.IsCriticalPod(pod) || hasPreventEvictionAnnotation(pod) {
+			protected[pod.UID] = true
+		}
+	}
+	if len(protected) == 0 {
+		return order
+	}
+	filtered := make([]types.UID, 0, len(order))
+	for _, uid := range order {
+		if !protected[uid] {
+			filtered = append(filtered, uid)
+		}
+	}
+	return filtered
+}
+
+// applyRankerPluginOrder reorders pods in place to match order, the UIDs
+// the plugin returned. Any pod the plugin omitted (a stale view, or a
+// plugin that dropped a pod it didn't recognize) is appended at the end in
+// its original relative order, so it is still a candidate rather than lost.
+func applyRankerPluginOrder(pods []*v1.Pod, order []types.UID) {
+	byUID := make(map[types.UID]*v1.Pod, len(pods))
+	for _, pod := range pods {
+		byUID[pod.UID] = pod
+	}
+	placed := make(map[types.UID]bool, len(order))
+	result := make([]*v1.Pod, 0, len(pods))
+	for _, uid := range order {
+		if placed[uid] {
+			// The plugin is untrusted input; a duplicate UID must not let
+			// result grow past len(pods), which would truncate a distinct
+			// pod out of the ranking when copied back below.
+			continue
+		}
+		if pod, ok := byUID[uid]; ok {
+			result = append(result, pod)
+			placed[uid] = true
+		}
+	}
+	for _, pod := range pods {
+		if !placed[pod.UID] {
+			result = append(result, pod)
+		}
+	}
+	copy(pods, result)
+}
+
+// setRankerGraceOverrides records the grace period overrides a ranker
+// plugin asked for, clamped to Config.MaxPodGracePeriodSeconds so a plugin
+// can never grant a pod more time to shut down than the node's own
+// configuration allows. Called with nil to clear any stale overrides from a
+// previous cycle when the plugin fails or is skipped this time around.
+func (m *managerImpl) setRankerGraceOverrides(overrides map[types.UID]int64) {
+	sanitized := make(map[types.UID]int64, len(overrides))
+	for uid, seconds := range overrides {
+		if seconds < 0 {
+			seconds = 0
+		}
+		if m.config.MaxPodGracePeriodSeconds > 0 && seconds > m.config.MaxPodGracePeriodSeconds {
+			seconds = m.config.MaxPodGracePeriodSeconds
+		}
+		sanitized[uid] = seconds
+	}
+	m.rankerLock.Lock()
+	m.rankerGraceOverrides = sanitized
+	m.rankerLock.Unlock()
+}
+
+// rankerGraceOverrideFor reports the sanitized grace period override a
+// ranker plugin requested for pod, if any.
+func (m *managerImpl) rankerGraceOverrideFor(uid types.UID) (int64, bool) {
+	m.rankerLock.Lock()
+	defer m.rankerLock.Unlock()
+	seconds, ok := m.rankerGraceOverrides[uid]
+	return seconds, ok
+}
+
+// podStatsUsageBytes reports the pod's working-set memory usage, the one
+// stat a ranking policy almost always wants, without handing the plugin the
+// full, much larger stats.PodStats payload.
+func podStatsUsageBytes(podStats statsapi.PodStats) uint64 {
+	if podStats.Memory == nil || podStats.Memory.WorkingSetBytes == nil {
+		return 0
+	}
+	return *podStats.Memory.WorkingSetBytes
+}
+
+// resolveRankerPlugin returns the RankerPlugin configured for signal via
+// Config.RankerPolicies, dialing it on first use and caching the connection
+// for the lifetime of the manager. Falls back to defaultRankerPlugin
+// wrapping legacy when unset, unrecognized, or the dial fails.
+func (m *managerImpl) resolveRankerPlugin(signal evictionapi.Signal, legacy rankFunc) RankerPlugin {
+	policy, ok := m.config.RankerPolicies[signal]
+	if !ok || policy == defaultRankerPolicy {
+		return defaultRankerPlugin{legacy: legacy}
+	}
+	if policy != remoteRankerPolicy {
+		return defaultRankerPlugin{legacy: legacy}
+	}
+
+	m.rankerLock.Lock()
+	defer m.rankerLock.Unlock()
+	if m.rankerPlugins == nil {
+		m.rankerPlugins = make(map[evictionapi.Signal]RankerPlugin)
+	}
+	if plugin, ok := m.rankerPlugins[signal]; ok {
+		return plugin
+	}
+	plugin, err := newRemoteRankerPlugin(m.config.RankerPluginSocket)
+	if err != nil {
+		klog.InfoS("Eviction manager: failed to dial ranker plugin, using the default ordering", "signal", signal, "socket", m.config.RankerPluginSocket, "err", err)
+		return defaultRankerPlugin{legacy: legacy}
+	}
+	m.rankerPlugins[signal] = plugin
+	return plugin
+}
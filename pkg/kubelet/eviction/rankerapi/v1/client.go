@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+)
+
+// rankerServiceName is the gRPC service a remote ranker plugin implements.
+const rankerServiceName = "rankerapi.v1.Ranker"
+
+// jsonCodecName is the gRPC content-subtype RankerClient negotiates.
+// RankRequest/RankResponse are plain structs, not generated proto.Message
+// types, so they can't go through grpc's default proto codec (its
+// codec.Marshal requires proto.Message); registering and selecting this
+// codec instead lets Rank actually put bytes on the wire rather than failing
+// to marshal on every call.
+const jsonCodecName = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec (de)serializes RankRequest/RankResponse as JSON instead of
+// protobuf, since this API has no .proto/protoc generation wired into the
+// build yet.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return jsonCodecName }
+
+// dialTimeout bounds how long Dial waits for the plugin socket to accept a
+// connection, matching the CRI/CSI remote-plugin dial convention elsewhere in
+// the kubelet.
+const dialTimeout = 10 * time.Second
+
+// RankerClient is the client-side stub for the Ranker gRPC service a remote
+// eviction-ranking plugin implements.
+type RankerClient interface {
+	// Rank asks the plugin to order req.Candidates for eviction.
+	Rank(ctx context.Context, req *RankRequest, opts ...grpc.CallOption) (*RankResponse, error)
+}
+
+// rankerClient is the default RankerClient implementation, backed by a dialed
+// grpc.ClientConn.
+type rankerClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewRankerClient wraps an established connection in a RankerClient.
+func NewRankerClient(cc *grpc.ClientConn) RankerClient {
+	return &rankerClient{cc: cc}
+}
+
+func (c *rankerClient) Rank(ctx context.Context, req *RankRequest, opts ...grpc.CallOption) (*RankResponse, error) {
+	resp := new(RankResponse)
+	callOpts := append([]grpc.CallOption{grpc.CallContentSubtype(jsonCodecName)}, opts...)
+	if err := c.cc.Invoke(ctx, "/"+rankerServiceName+"/Rank", req, resp, callOpts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Dial connects to a remote ranker plugin at addr, a unix socket path
+// optionally prefixed with "unix://" (e.g.
+// "unix:///var/lib/kubelet/plugins/eviction-ranker.sock"), the same address
+// convention CRI and device plugins use.
+func Dial(addr string) (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	return grpc.DialContext(ctx, addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(unixDialer),
+		grpc.WithBlock(),
+	)
+}
+
+// unixDialer strips an optional "unix://" scheme and dials the remainder as a
+// unix socket path.
+func unixDialer(ctx context.Context, addr string) (net.Conn, error) {
+	addr = strings.TrimPrefix(addr, "unix://")
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", addr)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+// fakeRanker is a minimal Ranker service implementation, standing in for a
+// real out-of-tree plugin binary.
+type fakeRanker struct {
+	resp *RankResponse
+	err  error
+	got  *RankRequest
+}
+
+func (f *fakeRanker) Rank(ctx context.Context, req *RankRequest) (*RankResponse, error) {
+	f.got = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.resp, nil
+}
+
+// rankerServiceDesc hand-rolls the grpc.ServiceDesc a protoc-gateway would
+// normally generate from a .proto file, since this package has none yet.
+var rankerServiceDesc = grpc.ServiceDesc{
+	ServiceName: rankerServiceName,
+	HandlerType: (*fakeRankerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Rank",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(RankRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				return srv.(fakeRankerServer).Rank(ctx, req)
+			},
+		},
+	},
+}
+
+// fakeRankerServer is the server-side counterpart of RankerClient.
+type fakeRankerServer interface {
+	Rank(ctx context.Context, req *RankRequest) (*RankResponse, error)
+}
+
+// TestRankEndToEnd dials a fake ranker plugin over a real unix socket and
+// confirms a RankRequest/RankResponse round-trips through the registered
+// JSON codec, the way it would against an actual out-of-tree plugin binary.
+func TestRankEndToEnd(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "ranker.sock")
+	lis, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listening on unix socket: %v", err)
+	}
+
+	want := &RankResponse{
+		OrderedUids:                []string{"pod-b", "pod-a"},
+		GracePeriodOverrideSeconds: map[string]int64{"pod-b": 5},
+	}
+	ranker := &fakeRanker{resp: want}
+
+	server := grpc.NewServer()
+	server.RegisterService(&rankerServiceDesc, fakeRankerServer(ranker))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	cc, err := Dial("unix://" + sockPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer cc.Close()
+
+	client := NewRankerClient(cc)
+	req := &RankRequest{
+		Signal: "memory.available",
+		Hard:   true,
+		Candidates: []*Candidate{
+			{Uid: "pod-a", Namespace: "default", Name: "a", UsageBytes: 100},
+			{Uid: "pod-b", Namespace: "default", Name: "b", UsageBytes: 200},
+		},
+	}
+
+	got, err := client.Rank(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Rank: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(ranker.got, req) {
+		t.Errorf("plugin received %+v, want %+v", ranker.got, req)
+	}
+}
+
+// TestRankEndToEndError confirms a plugin-returned error surfaces through
+// Rank rather than being swallowed by the JSON codec's (de)serialization.
+func TestRankEndToEndError(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "ranker.sock")
+	lis, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listening on unix socket: %v", err)
+	}
+
+	ranker := &fakeRanker{err: errors.New("plugin unavailable")}
+	server := grpc.NewServer()
+	server.RegisterService(&rankerServiceDesc, fakeRankerServer(ranker))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	cc, err := Dial("unix://" + sockPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer cc.Close()
+
+	client := NewRankerClient(cc)
+	if _, err := client.Rank(context.Background(), &RankRequest{Signal: "memory.available"}); err == nil {
+		t.Error("Rank() succeeded, want the plugin's error to surface")
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1 defines the wire types and gRPC client for the out-of-tree
+// eviction-candidate ranker plugin API, the remote counterpart to the
+// in-process PodRanker interface in pkg/kubelet/eviction. A plugin
+// implements the Ranker service over a unix socket; the kubelet dials it
+// once per configured signal and caches the connection for the lifetime of
+// the eviction manager.
+package v1
+
+// RankRequest describes one eviction-pressure signal and the pod candidates
+// competing for eviction this cycle.
+type RankRequest struct {
+	// Signal is the evictionapi.Signal string under pressure, e.g. "memory.available".
+	Signal string
+	// Hard reports whether Signal crossed a hard (immediate) rather than a
+	// soft (grace-period) eviction threshold.
+	Hard bool
+	// Threshold is the threshold value that was crossed, formatted the same
+	// way evictionapi.ThresholdValue.Quantity.String() would.
+	Threshold string
+	// Candidates are the pods eligible for eviction this cycle, in no
+	// particular order; the plugin is responsible for ordering them.
+	Candidates []*Candidate
+}
+
+// Candidate is a single pod eligible for eviction this cycle.
+type Candidate struct {
+	Uid        string
+	Namespace  string
+	Name       string
+	// UsageBytes is the candidate's working-set memory usage, the one stat a
+	// ranking policy almost always wants. Zero when usage couldn't be read.
+	UsageBytes uint64
+}
+
+// RankResponse is the plugin's answer: candidates in most-to-least-preferred
+// eviction order, plus any per-pod grace period overrides.
+type RankResponse struct {
+	// OrderedUids lists Candidate.Uid values from RankRequest, most- to
+	// least-preferred for eviction. Omitting a UID is allowed; the caller
+	// appends any omitted candidate to the end of the order instead of
+	// dropping it.
+	OrderedUids []string
+	// GracePeriodOverrideSeconds optionally overrides the threshold's default
+	// grace period for specific pod UIDs. The caller clamps every value to
+	// Config.MaxPodGracePeriodSeconds before acting on it.
+	GracePeriodOverrideSeconds map[string]int64
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+)
+
+// evictionDetailsAnnotation carries a StructuredEvictionReason as JSON, so
+// controllers and audit pipelines can distinguish node-pressure evictions
+// from OOMKills and API-initiated evictions without regexing the free-form
+// status message.
+const evictionDetailsAnnotation = "eviction.kubelet.kubernetes.io/details"
+
+// signalDisruptionReasons maps each signal to the typed DisruptionTarget
+// PodCondition Reason used for it, following the
+// "TerminationByKubelet:<Signal>" convention.
+var signalDisruptionReasons = map[evictionapi.Signal]string{
+	evictionapi.SignalMemoryAvailable:            "TerminationByKubelet:MemoryPressure",
+	evictionapi.SignalAllocatableMemoryAvailable: "TerminationByKubelet:MemoryPressure",
+	evictionapi.SignalNodeFsAvailable:            "TerminationByKubelet:DiskPressure",
+	evictionapi.SignalImageFsAvailable:           "TerminationByKubelet:DiskPressure",
+	evictionapi.SignalPIDAvailable:               "TerminationByKubelet:PIDPressure",
+	evictionapi.SignalAllocatablePIDAvailable:    "TerminationByKubelet:PIDPressure",
+}
+
+// defaultDisruptionReason is used for signals not named in
+// signalDisruptionReasons (e.g. a new PSI signal), so the taxonomy degrades
+// gracefully instead of panicking on an unmapped signal.
+const defaultDisruptionReason = "TerminationByKubelet"
+
+// StructuredEvictionReason captures why the eviction manager chose to kill a
+// pod, in machine-parseable form: the triggering signal, the observed vs
+// threshold values, whether it was a soft or hard threshold, the ranking
+// rationale, and whether a PDB-respecting API eviction was attempted first.
+type StructuredEvictionReason struct {
+	Signal               evictionapi.Signal `json:"signal"`
+	Observed             string             `json:"observed"`
+	Threshold            string             `json:"threshold"`
+	Soft                 bool               `json:"soft"`
+	RankingRationale     string             `json:"rankingRationale"`
+	APIEvictionAttempted bool               `json:"apiEvictionAttempted"`
+}
+
+// buildEvictionReason assembles a StructuredEvictionReason for the pod about
+// to be evicted for signal/resourceToReclaim.
+func buildEvictionReason(thresholdToReclaim evictionapi.Threshold, observed string, rankingRationale string, apiEvictionAttempted bool) StructuredEvictionReason {
+	return StructuredEvictionReason{
+		Signal:               thresholdToReclaim.Signal,
+		Observed:             observed,
+		Threshold:            fmt.Sprintf("%v", thresholdToReclaim.Value),
+		Soft:                 !isHardEvictionThreshold(thresholdToReclaim),
+		RankingRationale:     rankingRationale,
+		APIEvictionAttempted: apiEvictionAttempted,
+	}
+}
+
+// annotations renders reason as the eviction.kubelet.kubernetes.io/details
+// annotation, merged into base (which may already carry other annotations,
+// e.g. from evictionMessage). Marshaling failures are not expected for this
+// struct, but are handled by omitting the annotation rather than panicking.
+func (r StructuredEvictionReason) annotations(base map[string]string) map[string]string {
+	encoded, err := json.Marshal(r)
+	if err != nil {
+		return base
+	}
+	out := make(map[string]string, len(base)+1)
+	for k, v := range base {
+		out[k] = v
+	}
+	out[evictionDetailsAnnotation] = string(encoded)
+	return out
+}
+
+// observedValue renders the current value of signal from observations for
+// inclusion in a StructuredEvictionReason, or "" if observations holds no
+// available value for signal.
+func observedValue(observations signalObservations, signal evictionapi.Signal) string {
+	observed, found := observations[signal]
+	if !found || observed.available == nil {
+		return ""
+	}
+	return observed.available.String()
+}
+
+// rankingRationale renders a human-readable explanation of a pod's position
+// in the ranked eviction order, for inclusion in a StructuredEvictionReason.
+func rankingRationale(rank, total int) string {
+	return fmt.Sprintf("ranked %d of %d eviction candidates", rank+1, total)
+}
+
+// condition builds the typed DisruptionTarget PodCondition for r, using
+// message as its human-readable Message.
+func (r StructuredEvictionReason) condition(message string) *v1.PodCondition {
+	reason, ok := signalDisruptionReasons[r.Signal]
+	if !ok {
+		reason = defaultDisruptionReason
+	}
+	return &v1.PodCondition{
+		Type:    v1.DisruptionTarget,
+		Status:  v1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
+)
+
+// defaultSoftReclaimWindow is used when Config.SoftReclaimWindow is unset.
+const defaultSoftReclaimWindow = 2 * time.Second
+
+// PodCgroupReclaimer tightens and restores a pod cgroup's memory controls,
+// giving the kernel a push to reclaim cache/anon pages from that cgroup
+// without killing the pod. Implementations are expected to write cgroup v2's
+// memory.high (and, where configured, memory.swap.max).
+type PodCgroupReclaimer interface {
+	// TightenMemoryHigh lowers the pod's memory.high (and swap.max, if the
+	// implementation is configured to) to encourage kernel reclaim.
+	TightenMemoryHigh(pod *v1.Pod) error
+	// Restore undoes TightenMemoryHigh, returning the pod's cgroup limits to
+	// what they were set to at admission.
+	Restore(pod *v1.Pod) error
+}
+
+// reclaimPodLevelResources gives the top-ranked eviction candidate a gentler
+// tier between node-level reclaim and a kill: it temporarily tightens the
+// pod's cgroup v2 memory.high, waits Config.SoftReclaimWindow, and
+// re-observes the signal. If the signal recovered past threshold+minReclaim,
+// the kill is skipped. It only applies to memory-pressure signals, since
+// memory.high is meaningless for disk or PID pressure.
+func (m *managerImpl) reclaimPodLevelResources(ctx context.Context, pod *v1.Pod, threshold evictionapi.Threshold) bool {
+	if m.podCgroupReclaimer == nil || !isMemorySignal(threshold.Signal) {
+		return false
+	}
+
+	if err := m.podCgroupReclaimer.TightenMemoryHigh(pod); err != nil {
+		klog.InfoS("Eviction manager: failed to tighten pod cgroup memory.high for soft reclaim", "pod", klog.KObj(pod), "err", err)
+		return false
+	}
+
+	window := m.config.SoftReclaimWindow
+	if window <= 0 {
+		window = defaultSoftReclaimWindow
+	}
+	m.clock.Sleep(window)
+
+	recovered, err := m.signalRecovered(ctx, threshold)
+	if err != nil {
+		klog.InfoS("Eviction manager: failed to re-observe signal after soft reclaim", "signal", threshold.Signal, "err", err)
+		recovered = false
+	}
+
+	if !recovered {
+		if restoreErr := m.podCgroupReclaimer.Restore(pod); restoreErr != nil {
+			klog.InfoS("Eviction manager: failed to restore pod cgroup after soft reclaim attempt", "pod", klog.KObj(pod), "err", restoreErr)
+		}
+		return false
+	}
+
+	// Pressure already recovered with memory.high tightened, so it's safe to
+	// restore the pod's normal cgroup limits now rather than leaving it
+	// throttled indefinitely until some later cycle happens to untighten it.
+	if restoreErr := m.podCgroupReclaimer.Restore(pod); restoreErr != nil {
+		klog.InfoS("Eviction manager: failed to restore pod cgroup after successful soft reclaim", "pod", klog.KObj(pod), "err", restoreErr)
+	}
+
+	metrics.EvictionSoftReclaimRecoveries.WithLabelValues(string(threshold.Signal)).Inc()
+	klog.InfoS("Eviction manager: soft reclaim recovered the signal, skipping eviction", "pod", klog.KObj(pod), "signal", threshold.Signal)
+	return true
+}
+
+// isMemorySignal reports whether signal is one of the memory-pressure
+// signals soft reclaim applies to.
+func isMemorySignal(signal evictionapi.Signal) bool {
+	return signal == evictionapi.SignalMemoryAvailable || signal == evictionapi.SignalAllocatableMemoryAvailable
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"time"
+
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+)
+
+// Config holds the kubelet's eviction manager configuration, assembled from
+// the --eviction-* flags.
+type Config struct {
+	// Thresholds defines the eviction thresholds to monitor.
+	Thresholds []evictionapi.Threshold
+
+	// PodCgroupRoot is the absolute cgroupfs path under which per-pod
+	// cgroups live, used to resolve a pod's cgroup for PSI and soft-reclaim
+	// reads.
+	PodCgroupRoot string
+
+	// MaxPodGracePeriodSeconds is the grace period used for a soft eviction;
+	// hard evictions use a grace period of 0. A ranker plugin's per-pod grace
+	// period override replaces either value and is capped at this one.
+	MaxPodGracePeriodSeconds int64
+
+	// PressureTransitionPeriod is the duration eviction manager must wait
+	// before transitioning out of a pressure condition.
+	PressureTransitionPeriod time.Duration
+
+	// KernelMemcgNotification enables the memcg notification API, rather
+	// than polling, to detect memory threshold crossings.
+	KernelMemcgNotification bool
+
+	// RankerPolicies selects, per signal, the named PodRanker policy to use
+	// when ordering eviction candidates instead of the legacy
+	// QoS->PriorityClass->usage ordering. Unset or unrecognized names fall
+	// back to the legacy ordering.
+	RankerPolicies map[evictionapi.Signal]string
+
+	// EvictionBurst caps how many ranked pods evictRankedPods may kill in a
+	// single synchronize cycle when MinReclaimAware keeps the cycle going
+	// past the first candidate. Defaults to 1 (today's one-kill-per-cycle
+	// behavior) when unset.
+	EvictionBurst int
+
+	// MinReclaimAware, when true, re-observes the threshold signal after
+	// each kill and keeps evicting down the ranked list (up to
+	// EvictionBurst pods) until the signal recovers past its minReclaim
+	// goal, instead of stopping after the first kill.
+	MinReclaimAware bool
+
+	// MaxEvictionFraction bounds maxEvictionCount's safeguard as a fraction
+	// (0-1] of the candidate pods on the node, protecting against a
+	// misconfigured EvictionBurst draining an entire node in one cycle.
+	// Defaults to defaultMaxEvictionFraction when zero.
+	MaxEvictionFraction float64
+
+	// SoftReclaimWindow is how long reclaimPodLevelResources waits after
+	// tightening a pod's cgroup memory.high before re-observing the signal
+	// to decide whether soft reclaim avoided a kill. Defaults to
+	// defaultSoftReclaimWindow when zero.
+	SoftReclaimWindow time.Duration
+
+	// EvictionOnlyByAPI requires evictPodRespectingPDB to gate every kill
+	// behind a successful policy/v1 Eviction API call, so
+	// PodDisruptionBudgets are honored before the kubelet kills a pod's
+	// containers outright. Ignored if no PodEvictionAPIClient is configured.
+	EvictionOnlyByAPI bool
+
+	// MaxConcurrentEvictions caps how many pods evictConcurrently may have
+	// in flight at once. Values <= 1 keep evictRankedPods on its serial
+	// path.
+	MaxConcurrentEvictions int
+
+	// MaxEvictionsPerMinute rate-limits evictions across the whole node,
+	// independent of MaxConcurrentEvictions, so a burst of pressure can't
+	// kill pods faster than the configured budget.
+	MaxEvictionsPerMinute int
+
+	// RankerPluginSocket is the gRPC socket newRemoteRankerPlugin dials for
+	// signals whose RankerPolicies entry is remoteRankerPolicy.
+	RankerPluginSocket string
+
+	// DryRun causes synchronize/localStorageEviction to walk the full
+	// decision pipeline and record an EvictionDecision for the pod that
+	// would have been killed, without calling killPodFunc.
+	DryRun bool
+
+	// DryRunSignals overrides DryRun on a per-signal basis.
+	DryRunSignals map[evictionapi.Signal]bool
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// PriorityClassSelector restricts a scoring rule to pods whose
+// PriorityClassName is in Names. A nil selector matches every pod.
+type PriorityClassSelector struct {
+	Names []string
+}
+
+// Matches reports whether priorityClassName is selected by s.
+func (s *PriorityClassSelector) Matches(priorityClassName string) bool {
+	if s == nil {
+		return true
+	}
+	for _, name := range s.Names {
+		if name == priorityClassName {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchScoringArgs configures treating a prefix of extended resources as a
+// shrinking, reclaimed pool (e.g. kubernetes.io/batch-cpu) rather than a
+// static one.
+type BatchScoringArgs struct {
+	// BatchResourcePrefix selects which extended resources are treated as
+	// batch resources. Defaults to "kubernetes.io/batch-" when empty.
+	BatchResourcePrefix string
+	// Weight this pool carries in the combined score.
+	Weight int64
+	// PriorityClassSelector restricts batch accounting to matching pods; nil
+	// matches every pod.
+	PriorityClassSelector *PriorityClassSelector
+}
+
+// ScoringStrategyType is the type of scoring strategy used by NodeResourcesFit.
+type ScoringStrategyType string
+
+const (
+	// LeastAllocated prefers nodes with fewer requested resources.
+	LeastAllocated ScoringStrategyType = "LeastAllocated"
+	// MostAllocated prefers nodes with more requested resources.
+	MostAllocated ScoringStrategyType = "MostAllocated"
+	// BalancedAllocation prefers nodes with balanced resource usage.
+	BalancedAllocation ScoringStrategyType = "BalancedAllocation"
+	// RequestedToCapacityRatio names the requested-to-capacity-ratio strategy.
+	// It has no built-in implementation: NewFit rejects it as unsupported
+	// unless a scorer is registered under this name through RegisterScorer.
+	RequestedToCapacityRatio ScoringStrategyType = "RequestedToCapacityRatio"
+)
+
+// ResourceSpec names a single resource and the weight it should carry when
+// multiple resources are combined into one score.
+type ResourceSpec struct {
+	// Name is the name of the resource, e.g. "cpu", "memory", or an extended
+	// resource such as "deviceclass/gpu.example.com".
+	Name string
+	// Weight of the resource.
+	Weight int64
+}
+
+// ScoringStrategy holds the parameters for the scoring strategy used by the
+// NodeResourcesFit plugin.
+type ScoringStrategy struct {
+	// Type selects which strategy to use, by name. Names registered through
+	// RegisterScorer take precedence over the built-ins of the same name.
+	Type ScoringStrategyType
+}
+
+// UtilizationTarget pairs a resource with the observed-utilization percentage
+// at which the Utilization scoring strategy scores it highest.
+type UtilizationTarget struct {
+	// Name is the resource name, e.g. "cpu" or "memory".
+	Name string
+	// Weight this resource carries in the combined score.
+	Weight int64
+	// Target utilization percentage (0-100) at which the score peaks.
+	Target float64
+}
+
+// NodeResourcesFitArgs holds the arguments used to configure the
+// NodeResourcesFit plugin.
+type NodeResourcesFitArgs struct {
+	metav1.TypeMeta
+
+	// ScoringStrategy selects the node scoring strategy.
+	ScoringStrategy *ScoringStrategy
+
+	// Resources lists the resources to be weighted and their weights,
+	// including DRA deviceclass/<classname> entries.
+	Resources []ResourceSpec
+
+	// UtilizationTargets configures the Utilization scoring strategy's
+	// target-load-packing curve, per resource. Only read when
+	// ScoringStrategy.Type is UtilizationScorerName.
+	UtilizationTargets []UtilizationTarget
+
+	// ElasticQuotaBorrowPenalty is the multiplier applied to a namespace's
+	// requested resources once it's borrowing past its ElasticQuota Min.
+	// Only read when ScoringStrategy.Type is ElasticQuotaScorerName.
+	ElasticQuotaBorrowPenalty float64
+
+	// BatchScoring configures reclaimed/batch extended-resource accounting.
+	// Nil disables it.
+	BatchScoring *BatchScoringArgs
+
+	// QoSWeights scales a pod's Overhead by its QoS class before folding it
+	// into the scored request, so Guaranteed pods pack tighter than
+	// BestEffort ones. Nil means no scaling.
+	QoSWeights map[v1.PodQOSClass]float64
+}
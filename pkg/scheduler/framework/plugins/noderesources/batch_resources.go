@@ -0,0 +1,180 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// withBatchResources attaches batch-aware accounting for args.BatchScoring to
+// an existing resourceAllocationScorer, e.g. the one built for LeastAllocated
+// or MostAllocated, so mixed nodes score batch and latency-sensitive pods
+// differently without needing a dedicated scorer implementation.
+func withBatchResources(base *resourceAllocationScorer, reclaimProvider NodeReclaimProvider, args *config.NodeResourcesFitArgs) *resourceAllocationScorer {
+	prefix := args.BatchScoring.BatchResourcePrefix
+	if prefix == "" {
+		prefix = defaultBatchResourcePrefix
+	}
+	base.batchResources = &batchResourceConfig{
+		prefix:          prefix,
+		weight:          args.BatchScoring.Weight,
+		reclaimProvider: reclaimProvider,
+		priorityClassSelector: func(pod *v1.Pod) bool {
+			return args.BatchScoring.PriorityClassSelector == nil || args.BatchScoring.PriorityClassSelector.Matches(pod.Spec.PriorityClassName)
+		},
+	}
+
+	// base.resourceToWeightMap drives both which resources score() bothers
+	// computing (it only iterates resources present in the map) and the
+	// weight allocationScore gives each one. A batch resource is otherwise
+	// invisible to scoring unless args.Resources happens to carry a matching
+	// ResourceSpec entry, which would make BatchScoring.Weight redundant with
+	// that entry's own Weight. Wire it in here instead, so BatchScoring.Weight
+	// alone is enough to have the pool scored.
+	if base.batchResources.weight != 0 {
+		for _, name := range []string{"cpu", "memory"} {
+			base.resourceToWeightMap[v1.ResourceName(prefix+name)] = base.batchResources.weight
+		}
+	}
+	return base
+}
+
+// defaultBatchResourcePrefix is used when NodeResourcesFitArgs.BatchScoring
+// doesn't set one, matching the kubernetes.io/batch-cpu, kubernetes.io/batch-memory
+// convention popularized by co-located batch scheduling (à la Koordinator).
+const defaultBatchResourcePrefix = "kubernetes.io/batch-"
+
+// NodeReclaimProvider reports how much of a node's primary resource (cpu or
+// memory) is actually free once real usage, not just requests, is accounted
+// for. This is the dynamic pool reclaimed/batch resources are scored against.
+type NodeReclaimProvider interface {
+	// ReclaimableCapacity returns node.Allocatable[primary] - actualUsage[primary].
+	ReclaimableCapacity(nodeName string, primary v1.ResourceName) (int64, error)
+}
+
+// batchResourceConfig configures how extended resources matching a name
+// prefix are treated as a shrinking, reclaimed pool rather than a static one.
+type batchResourceConfig struct {
+	prefix          string
+	weight          int64
+	reclaimProvider NodeReclaimProvider
+	// priorityClassSelector restricts which pods are treated as batch
+	// consumers for the purposes of this accounting; pods that don't match
+	// are scored through the normal scalar-resource path instead.
+	priorityClassSelector func(pod *v1.Pod) bool
+}
+
+// primaryResourceFor maps a batch resource name, e.g.
+// "kubernetes.io/batch-cpu", to the primary resource it shares a pool with.
+func (b *batchResourceConfig) primaryResourceFor(resource v1.ResourceName) (v1.ResourceName, bool) {
+	name := strings.TrimPrefix(string(resource), b.prefix)
+	switch name {
+	case "cpu":
+		return v1.ResourceCPU, true
+	case "memory":
+		return v1.ResourceMemory, true
+	default:
+		return "", false
+	}
+}
+
+// isBatchResource reports whether resource matches this config's configured
+// BatchResourcePrefix.
+func (b *batchResourceConfig) isBatchResource(resource v1.ResourceName) bool {
+	prefix := b.prefix
+	if prefix == "" {
+		prefix = defaultBatchResourcePrefix
+	}
+	return strings.HasPrefix(string(resource), prefix)
+}
+
+// calculateBatchAllocatableRequest computes the shrinking batch pool for
+// resource on nodeInfo's node, and the sum of batch and non-batch pods'
+// requests against it, applying b.weight's intent by returning the pair
+// unscaled — callers weight it the same way as any other resourceToWeightMap
+// entry.
+func (b *batchResourceConfig) calculateBatchAllocatableRequest(nodeInfo *framework.NodeInfo, pod *v1.Pod, resource v1.ResourceName) (int64, int64) {
+	if b.priorityClassSelector != nil && !b.priorityClassSelector(pod) {
+		return 0, 0
+	}
+	primary, ok := b.primaryResourceFor(resource)
+	if !ok || b.reclaimProvider == nil {
+		return 0, 0
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return 0, 0
+	}
+
+	allocatable, err := b.reclaimProvider.ReclaimableCapacity(node.Name, primary)
+	if err != nil {
+		return 0, 0
+	}
+
+	var requested int64
+	for _, p := range nodeInfo.Pods {
+		if batchReq := sumContainerRequests(p.Pod, resource); batchReq > 0 {
+			// The pod is itself a batch consumer of this resource.
+			requested += batchReq
+			continue
+		}
+		// A non-batch pod still draws from the same reclaimed pool through
+		// its plain cpu/memory request, so it has to count against it too —
+		// otherwise a pending latency-sensitive pod, not yet reflected in
+		// reclaimProvider's actual-usage figure, would never shrink the pool.
+		requested += sumPrimaryResourceRequests(p.Pod, primary)
+	}
+	requested += sumContainerRequests(pod, resource)
+
+	return allocatable, requested
+}
+
+// sumContainerRequests sums resource across a pod's regular containers.
+// Unlike calculatePodResourceRequest, it does not count init containers or
+// pod overhead.
+func sumContainerRequests(pod *v1.Pod, resource v1.ResourceName) int64 {
+	var total int64
+	for i := range pod.Spec.Containers {
+		if q, ok := pod.Spec.Containers[i].Resources.Requests[resource]; ok {
+			total += q.Value()
+		}
+	}
+	return total
+}
+
+// sumPrimaryResourceRequests sums a pod's plain cpu or memory requests, in the
+// same units as nodeInfo.Allocatable/Requested (milli-cores for cpu, bytes for
+// everything else), so it's directly comparable to reclaimProvider's figures.
+func sumPrimaryResourceRequests(pod *v1.Pod, primary v1.ResourceName) int64 {
+	var total int64
+	for i := range pod.Spec.Containers {
+		q, ok := pod.Spec.Containers[i].Resources.Requests[primary]
+		if !ok {
+			continue
+		}
+		if primary == v1.ResourceCPU {
+			total += q.MilliValue()
+		} else {
+			total += q.Value()
+		}
+	}
+	return total
+}
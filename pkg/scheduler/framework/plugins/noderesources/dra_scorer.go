@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	resourcev1alpha1listers "k8s.io/client-go/listers/resource/v1alpha1"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+// claimAwareResourceAllocationScorer returns a resourceAllocationScorer that
+// folds DRA device availability into the CPU/memory weighted score using
+// scorerFn (the wrapped strategy's scoring function), and wires up the
+// lister and provider needed to resolve deviceclass/<classname> entries in
+// args.Resources. useRequested is carried over from the wrapped strategy so
+// DRA-awareness doesn't change whether CPU/memory are scored off Requested or
+// NonZeroRequested.
+func claimAwareResourceAllocationScorer(name string, scorerFn func(requested, allocable resourceToValueMap) int64, useRequested bool, resourceClassLister resourcev1alpha1listers.ResourceClassLister, claimAvailabilityProvider ClaimAvailabilityProvider, args *config.NodeResourcesFitArgs) *resourceAllocationScorer {
+	return &resourceAllocationScorer{
+		Name:                      name,
+		useRequested:              useRequested,
+		scorer:                    scorerFn,
+		resourceToWeightMap:       resourcesToWeightMap(args.Resources),
+		resourceClassLister:       resourceClassLister,
+		claimAvailabilityProvider: claimAvailabilityProvider,
+	}
+}
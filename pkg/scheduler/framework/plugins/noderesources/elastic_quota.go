@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// ElasticQuotaScorerName is the name under which the ElasticQuota-aware
+// scorer is registered.
+const ElasticQuotaScorerName = "ElasticQuotaFit"
+
+// NamespaceQuota is the Min/Max/Used view of a namespace's ElasticQuota, for
+// the resources it constrains.
+type NamespaceQuota struct {
+	Min  map[v1.ResourceName]int64
+	Max  map[v1.ResourceName]int64
+	Used map[v1.ResourceName]int64
+}
+
+// ElasticQuotaLister indexes ElasticQuota custom resources by namespace, the
+// same way resourceClassLister indexes ResourceClasses by name.
+type ElasticQuotaLister interface {
+	GetNamespaceQuota(namespace string) (*NamespaceQuota, bool)
+}
+
+// elasticQuotaResourceAllocationScorer treats a node's effective allocatable
+// as min(node.Allocatable, remainingQuotaInNamespace), and its effective
+// requested as sum(nsUsage) + podRequest (scaled by the borrow penalty past
+// Min) or the node's own requested + podRequest, whichever is larger, so
+// tenants that are borrowing past their ElasticQuota Min prefer emptier nodes.
+type elasticQuotaResourceAllocationScorer struct {
+	resourceAllocationScorer
+	quotaLister   ElasticQuotaLister
+	borrowPenalty float64
+}
+
+// NewElasticQuotaScorer wraps baseScorer so calculateResourceAllocatableRequest
+// accounts for the pod namespace's ElasticQuota instead of only the node's raw
+// allocatable/requested.
+func NewElasticQuotaScorer(baseScorer *resourceAllocationScorer, quotaLister ElasticQuotaLister, borrowPenalty float64) *elasticQuotaResourceAllocationScorer {
+	s := &elasticQuotaResourceAllocationScorer{
+		resourceAllocationScorer: *baseScorer,
+		quotaLister:              quotaLister,
+		borrowPenalty:            borrowPenalty,
+	}
+	s.Name = ElasticQuotaScorerName
+	return s
+}
+
+// NodeScore recomputes the per-resource (allocatable, requested) pairs through
+// effectiveAllocatableRequest before handing them to the embedded scorer
+// function, so calculateResourceAllocatableRequest's node-wide view is
+// replaced with the namespace's ElasticQuota-adjusted view.
+func (s *elasticQuotaResourceAllocationScorer) NodeScore(pod *v1.Pod, nodeInfo *framework.NodeInfo) (int64, *framework.Status) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return 0, framework.NewStatus(framework.Error, "node not found")
+	}
+
+	requested := make(resourceToValueMap)
+	allocatable := make(resourceToValueMap)
+	for resource := range s.resourceToWeightMap {
+		nodeAlloc, nodeReq := s.calculateResourceAllocatableRequest(nodeInfo, pod, resource)
+		if nodeAlloc == 0 {
+			continue
+		}
+		podRequest := s.calculatePodResourceRequest(pod, resource)
+		allocatable[resource], requested[resource] = s.effectiveAllocatableRequest(pod.Namespace, resource, nodeAlloc, nodeReq-podRequest, podRequest)
+	}
+
+	score := s.scorer(requested, allocatable)
+	klog.V(10).InfoS("ElasticQuota-adjusted resource scoring", "pod", klog.KObj(pod), "node", klog.KObj(node),
+		"allocatableResource", allocatable, "requestedResource", requested, "resourceScore", score)
+	return score, nil
+}
+
+// effectiveAllocatableRequest adjusts the node-wide (allocatable, requested)
+// pair computed by resourceAllocationScorer.calculateResourceAllocatableRequest
+// for the ElasticQuota of namespace, applying the borrow penalty once the
+// namespace is over its Min.
+func (s *elasticQuotaResourceAllocationScorer) effectiveAllocatableRequest(namespace string, resource v1.ResourceName, nodeAllocatable, nodeRequested, podRequest int64) (int64, int64) {
+	quota, ok := s.quotaLister.GetNamespaceQuota(namespace)
+	if !ok {
+		// No ElasticQuota for this namespace: score normally.
+		return nodeAllocatable, nodeRequested
+	}
+
+	allocatable := nodeAllocatable
+	if remaining, ok := remainingQuota(quota, resource); ok && remaining < allocatable {
+		allocatable = remaining
+	}
+
+	used := quota.Used[resource]
+	requested := used + podRequest
+
+	if min, ok := quota.Min[resource]; ok && used > min {
+		// Over Min but (by definition of remainingQuota) still under Max:
+		// apply the borrow penalty so this tenant prefers emptier nodes.
+		requested = int64(float64(requested) * (1 + s.borrowPenalty))
+	}
+
+	// The namespace's quota usage is shared across every node, so it alone
+	// can't tell two equally-allocatable nodes apart. Fold in this node's
+	// own load too, so a node that's already busier than the quota-implied
+	// floor still scores as busier, preserving "prefer emptier nodes".
+	if nodeRequested+podRequest > requested {
+		requested = nodeRequested + podRequest
+	}
+
+	return allocatable, requested
+}
+
+// remainingQuota returns quota.Max[resource]-quota.Used[resource] and whether
+// the resource is constrained by this ElasticQuota at all.
+func remainingQuota(quota *NamespaceQuota, resource v1.ResourceName) (int64, bool) {
+	max, ok := quota.Max[resource]
+	if !ok {
+		return 0, false
+	}
+	return max - quota.Used[resource], true
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	nodev1listers "k8s.io/client-go/listers/node/v1"
+	resourcev1alpha1listers "k8s.io/client-go/listers/resource/v1alpha1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// Name is the name of the plugin used in the plugin registry and configs.
+const Name = "NodeResourcesFit"
+
+// nodeScorer is the interface every resourceAllocationScorer variant in this
+// package satisfies, letting Fit.Score stay agnostic to which strategy was
+// selected.
+type nodeScorer interface {
+	NodeScore(pod *v1.Pod, nodeInfo *framework.NodeInfo) (int64, *framework.Status)
+}
+
+// baseScorerAdapter lets the plain resourceAllocationScorer (whose scoring
+// method is named score, not NodeScore) satisfy nodeScorer.
+type baseScorerAdapter struct {
+	*resourceAllocationScorer
+}
+
+func (a baseScorerAdapter) NodeScore(pod *v1.Pod, nodeInfo *framework.NodeInfo) (int64, *framework.Status) {
+	return a.resourceAllocationScorer.score(pod, nodeInfo)
+}
+
+// Fit is a score plugin that favors nodes based on their resource allocation.
+type Fit struct {
+	handle framework.Handle
+	scorer nodeScorer
+}
+
+var _ framework.ScorePlugin = &Fit{}
+
+// Name returns the plugin's name, NodeResourcesFit.
+func (f *Fit) Name() string {
+	return Name
+}
+
+// Score invokes the strategy selected by NewFit against nodeName.
+func (f *Fit) Score(ctx context.Context, _ *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	nodeInfo, err := f.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	return f.scorer.NodeScore(pod, nodeInfo)
+}
+
+// ScoreExtensions returns nil; NodeResourcesFit does not normalize scores
+// across nodes.
+func (f *Fit) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// NewFit builds a Fit plugin for plArgs, the entry point kube-scheduler uses
+// when a profile lists "NodeResourcesFit" as a score plugin. It resolves
+// args.ScoringStrategy.Type against the RegisterScorer registry first and
+// then the built-in LeastAllocated/MostAllocated/BalancedAllocation
+// strategies, then layers in DRA-aware
+// scoring when the handle exposes a ResourceClassLister and
+// ClaimAvailabilityProvider, so deviceclass/<classname> entries in
+// args.Resources actually affect node scores.
+func NewFit(_ context.Context, plArgs runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	args, ok := plArgs.(*config.NodeResourcesFitArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type NodeResourcesFitArgs, got %T", plArgs)
+	}
+	if args.ScoringStrategy == nil {
+		return nil, fmt.Errorf("scoring strategy not specified")
+	}
+
+	base := resolveScorer(args, builtinScorer(args.ScoringStrategy.Type))(args)
+	if base == nil {
+		return nil, fmt.Errorf("unsupported scoring strategy %q", args.ScoringStrategy.Type)
+	}
+
+	if resourceClassLister, claimProvider, ok := draDependencies(h); ok {
+		base = claimAwareResourceAllocationScorer(base.Name, base.scorer, base.useRequested, resourceClassLister, claimProvider, args)
+	}
+
+	base.qosWeights = args.QoSWeights
+	if lister, ok := h.(interface {
+		RuntimeClassLister() nodev1listers.RuntimeClassLister
+	}); ok {
+		base.runtimeClassLister = lister.RuntimeClassLister()
+	}
+
+	if args.BatchScoring != nil {
+		if provider, ok := h.(NodeReclaimProvider); ok {
+			base = withBatchResources(base, provider, args)
+		} else {
+			klog.V(2).InfoS("BatchScoring configured but handle does not implement NodeReclaimProvider, ignoring", "plugin", Name)
+		}
+	}
+
+	scorer := nodeScorer(baseScorerAdapter{base})
+	switch args.ScoringStrategy.Type {
+	case UtilizationScorerName:
+		if provider, ok := h.(NodeUsageProvider); ok {
+			scorer = NewUtilizationScorer(provider, base, args)
+		} else {
+			klog.V(2).InfoS("Utilization strategy configured but handle does not implement NodeUsageProvider, falling back to LeastAllocated", "plugin", Name)
+		}
+	case ElasticQuotaScorerName:
+		if quotaLister, ok := h.(ElasticQuotaLister); ok {
+			scorer = NewElasticQuotaScorer(base, quotaLister, args.ElasticQuotaBorrowPenalty)
+		} else {
+			klog.V(2).InfoS("ElasticQuotaFit strategy configured but handle does not implement ElasticQuotaLister, falling back to LeastAllocated", "plugin", Name)
+		}
+	}
+
+	return &Fit{handle: h, scorer: scorer}, nil
+}
+
+// builtinScorer returns the factory for one of the classic built-in
+// strategies, or a factory that returns nil for an unrecognized name so
+// NewFit can surface a clear error.
+func builtinScorer(name config.ScoringStrategyType) scorer {
+	switch name {
+	case config.LeastAllocated:
+		return leastAllocatedScorer
+	case config.MostAllocated:
+		return mostAllocatedScorer
+	case config.BalancedAllocation:
+		return balancedAllocationScorer
+	case UtilizationScorerName, ElasticQuotaScorerName:
+		// Both strategies are layered on top of LeastAllocated by default;
+		// NewFit swaps in their dedicated NodeScore implementation above
+		// when the handle supports it.
+		return leastAllocatedScorer
+	default:
+		return func(*config.NodeResourcesFitArgs) *resourceAllocationScorer { return nil }
+	}
+}
+
+// leastAllocatedScorer favors nodes with fewer requested resources.
+func leastAllocatedScorer(args *config.NodeResourcesFitArgs) *resourceAllocationScorer {
+	weights := resourcesToWeightMap(args.Resources)
+	return &resourceAllocationScorer{
+		Name:                string(config.LeastAllocated),
+		resourceToWeightMap: weights,
+		scorer: func(requested, allocable resourceToValueMap) int64 {
+			return allocationScore(requested, allocable, weights, true)
+		},
+	}
+}
+
+// mostAllocatedScorer favors nodes with more requested resources.
+func mostAllocatedScorer(args *config.NodeResourcesFitArgs) *resourceAllocationScorer {
+	weights := resourcesToWeightMap(args.Resources)
+	return &resourceAllocationScorer{
+		Name:                string(config.MostAllocated),
+		useRequested:        true,
+		resourceToWeightMap: weights,
+		scorer: func(requested, allocable resourceToValueMap) int64 {
+			return allocationScore(requested, allocable, weights, false)
+		},
+	}
+}
+
+// balancedAllocationScorer favors nodes whose resources are requested in
+// similar proportions to each other, avoiding nodes that are lopsided (e.g.
+// CPU-saturated but memory-idle).
+func balancedAllocationScorer(args *config.NodeResourcesFitArgs) *resourceAllocationScorer {
+	weights := resourcesToWeightMap(args.Resources)
+	return &resourceAllocationScorer{
+		Name:                string(config.BalancedAllocation),
+		useRequested:        true,
+		resourceToWeightMap: weights,
+		scorer: func(requested, allocable resourceToValueMap) int64 {
+			var minFraction, maxFraction float64
+			first := true
+			for resource, alloc := range allocable {
+				if alloc == 0 {
+					continue
+				}
+				fraction := float64(requested[resource]) / float64(alloc)
+				if first {
+					minFraction, maxFraction, first = fraction, fraction, false
+					continue
+				}
+				if fraction < minFraction {
+					minFraction = fraction
+				}
+				if fraction > maxFraction {
+					maxFraction = fraction
+				}
+			}
+			if first {
+				return 0
+			}
+			return int64((1 - (maxFraction - minFraction)) * float64(framework.MaxNodeScore))
+		},
+	}
+}
+
+// allocationScore implements the LeastAllocated/MostAllocated formula:
+// weighted average of each resource's (un)requested fraction of allocatable.
+func allocationScore(requested, allocable resourceToValueMap, weights resourceToWeightMap, least bool) int64 {
+	var score, weightSum int64
+	for resource, alloc := range allocable {
+		weight := weights[resource]
+		weightSum += weight
+		if alloc == 0 {
+			continue
+		}
+		fraction := float64(requested[resource]) / float64(alloc)
+		if least {
+			fraction = 1 - fraction
+		}
+		score += int64(fraction*float64(framework.MaxNodeScore)) * weight
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return score / weightSum
+}
+
+// draDependencies reports whether h exposes both the ResourceClassLister and
+// ClaimAvailabilityProvider DRA scoring needs, returning ok=false when either
+// is absent so callers skip DRA-aware scoring instead of failing plugin
+// construction.
+func draDependencies(h framework.Handle) (resourcev1alpha1listers.ResourceClassLister, ClaimAvailabilityProvider, bool) {
+	lister, ok := h.(interface {
+		ResourceClassLister() resourcev1alpha1listers.ResourceClassLister
+	})
+	if !ok {
+		return nil, nil, false
+	}
+	provider, ok := h.(ClaimAvailabilityProvider)
+	if !ok {
+		return nil, nil, false
+	}
+	return lister.ResourceClassLister(), provider, true
+}
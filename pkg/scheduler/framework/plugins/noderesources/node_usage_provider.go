@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"golang.org/x/sync/singleflight"
+)
+
+// nodeUsageCacheTTL bounds how stale a NodeUsageProvider reading may be
+// before it's re-fetched; kept short enough that the Score extension point
+// stays responsive to real load changes, but long enough to absorb repeated
+// Score calls for the same scheduling cycle.
+const nodeUsageCacheTTL = 10 * time.Second
+
+// NodeUsageProvider reports a node's observed (as opposed to requested)
+// utilization for the resources it knows about, keyed by resource name and
+// expressed as a fraction of capacity in [0, 1].
+type NodeUsageProvider interface {
+	NodeUsage(nodeName string) (map[v1.ResourceName]float64, error)
+}
+
+// cachingNodeUsageProvider wraps a NodeUsageProvider with a short TTL cache
+// and singleflight de-duplication, so concurrent Score calls for the same
+// node during one scheduling cycle collapse into a single upstream fetch.
+type cachingNodeUsageProvider struct {
+	delegate NodeUsageProvider
+	group    singleflight.Group
+
+	mu    sync.RWMutex
+	cache map[string]cachedNodeUsage
+}
+
+type cachedNodeUsage struct {
+	usage     map[v1.ResourceName]float64
+	fetchedAt time.Time
+}
+
+// newCachingNodeUsageProvider wraps delegate with TTL caching.
+func newCachingNodeUsageProvider(delegate NodeUsageProvider) *cachingNodeUsageProvider {
+	return &cachingNodeUsageProvider{
+		delegate: delegate,
+		cache:    make(map[string]cachedNodeUsage),
+	}
+}
+
+// NodeUsage returns the cached reading for nodeName, refreshing it through
+// the delegate provider if it's missing or older than nodeUsageCacheTTL.
+func (c *cachingNodeUsageProvider) NodeUsage(nodeName string) (map[v1.ResourceName]float64, error) {
+	c.mu.RLock()
+	entry, ok := c.cache[nodeName]
+	c.mu.RUnlock()
+	if ok && time.Since(entry.fetchedAt) < nodeUsageCacheTTL {
+		return entry.usage, nil
+	}
+
+	v, err, _ := c.group.Do(nodeName, func() (interface{}, error) {
+		usage, err := c.delegate.NodeUsage(nodeName)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.cache[nodeName] = cachedNodeUsage{usage: usage, fetchedAt: time.Now()}
+		c.mu.Unlock()
+		return usage, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[v1.ResourceName]float64), nil
+}
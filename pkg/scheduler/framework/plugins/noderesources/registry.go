@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+// scorerRegistry lets out-of-tree strategies (entropy-based,
+// fragmentation-aware, PMEM-tiered, ...) be linked into a custom scheduler
+// binary under NodeResourcesFitArgs.ScoringStrategy.Type without forking this
+// package, matching the out-of-tree plugin pattern used for filter/score
+// plugins elsewhere in the framework.
+var scorerRegistry = struct {
+	mu     sync.RWMutex
+	byName map[string]scorer
+}{byName: map[string]scorer{}}
+
+// RegisterScorer registers factory under name for later lookup by
+// NodeResourcesFitArgs.ScoringStrategy.Type. It returns an error if name is
+// already registered.
+func RegisterScorer(name string, factory scorer) error {
+	scorerRegistry.mu.Lock()
+	defer scorerRegistry.mu.Unlock()
+	if _, exists := scorerRegistry.byName[name]; exists {
+		return fmt.Errorf("scorer %q is already registered", name)
+	}
+	scorerRegistry.byName[name] = factory
+	return nil
+}
+
+// MustRegisterScorer is like RegisterScorer but panics on error, for use in
+// package init() functions where a duplicate name is a programming error.
+func MustRegisterScorer(name string, factory scorer) {
+	if err := RegisterScorer(name, factory); err != nil {
+		panic(err)
+	}
+}
+
+// lookupScorer returns the factory registered under name, or nil if none is
+// registered — the plugin factory falls back to its built-ins in that case.
+func lookupScorer(name string) scorer {
+	scorerRegistry.mu.RLock()
+	defer scorerRegistry.mu.RUnlock()
+	return scorerRegistry.byName[name]
+}
+
+// ListScorers returns the names of all registered out-of-tree scorers, sorted
+// alphabetically, for kube-scheduler --help diagnostics.
+func ListScorers() []string {
+	scorerRegistry.mu.RLock()
+	defer scorerRegistry.mu.RUnlock()
+	names := make([]string, 0, len(scorerRegistry.byName))
+	for name := range scorerRegistry.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveScorer looks up args.ScoringStrategy.Type in the registry first, so
+// vendors can override or add to the built-in strategy names, falling back to
+// fn (one of the built-in factories) when nothing is registered under that
+// name.
+func resolveScorer(args *config.NodeResourcesFitArgs, fn scorer) scorer {
+	if args.ScoringStrategy != nil {
+		if registered := lookupScorer(string(args.ScoringStrategy.Type)); registered != nil {
+			return registered
+		}
+	}
+	return fn
+}
@@ -17,13 +17,37 @@ limitations under the License.
 package noderesources
 
 import (
+	"strings"
+
 	v1 "k8s.io/api/core/v1"
+	resourcev1alpha1 "k8s.io/api/resource/v1alpha1"
+	nodev1listers "k8s.io/client-go/listers/node/v1"
+	resourcev1alpha1listers "k8s.io/client-go/listers/resource/v1alpha1"
 	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
 	"k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
 )
 
+// deviceClassResourcePrefix is the ResourceSpec name convention used to weight
+// DRA devices next to CPU/memory in a scheduler profile, e.g.
+// "deviceclass/gpu.example.com".
+const deviceClassResourcePrefix = "deviceclass/"
+
+// ClaimAvailabilityProvider reports how many equivalent resources of a
+// ResourceClass a node still has free. Implementations typically delegate to
+// the class's driver (e.g. over the DRA kubelet plugin gRPC socket).
+type ClaimAvailabilityProvider interface {
+	Available(node *v1.Node, class *resourcev1alpha1.ResourceClass) (int64, error)
+	// ClaimsForClass reports how many of pod's ResourceClaims actually
+	// resolve to class, so a pod whose claims span multiple classes isn't
+	// counted as demand against every class it claims from. Implementations
+	// resolve this the same way they resolve Available: by asking the
+	// class's driver, or by reading the bound ResourceClaim objects.
+	ClaimsForClass(pod *v1.Pod, class *resourcev1alpha1.ResourceClass) int64
+}
+
 // resourceToWeightMap contains resource name and weight.
 type resourceToWeightMap map[v1.ResourceName]int64
 
@@ -38,6 +62,24 @@ type resourceAllocationScorer struct {
 	useRequested        bool
 	scorer              func(requested, allocable resourceToValueMap) int64
 	resourceToWeightMap resourceToWeightMap
+	// resourceClassLister resolves deviceclass/<classname> resources to their
+	// ResourceClass so claimAvailabilityProvider can be asked about the node's
+	// remaining capacity. Nil unless the scorer was built with DRA awareness.
+	resourceClassLister resourcev1alpha1listers.ResourceClassLister
+	// claimAvailabilityProvider reports how many equivalent resources of a
+	// ResourceClass a node still has free, e.g. by asking the class's driver.
+	claimAvailabilityProvider ClaimAvailabilityProvider
+	// batchResources configures treating a prefix of extended resources
+	// (e.g. kubernetes.io/batch-cpu) as a shrinking, reclaimed pool rather
+	// than a static one. Nil unless the scorer was built with batch scoring.
+	batchResources *batchResourceConfig
+	// qosWeights scales a pod's overhead by its QoS class before it's folded
+	// into podRequest, so Guaranteed pods pack tighter than BestEffort ones.
+	// Nil means no scaling (factor of 1.0 for every class).
+	qosWeights map[v1.PodQOSClass]float64
+	// runtimeClassLister resolves a pod's RuntimeClassName so its
+	// RuntimeClass.Overhead.PodFixed can be preferred over pod.Spec.Overhead.
+	runtimeClassLister nodev1listers.RuntimeClassLister
 }
 
 // resourceToValueMap is keyed with resource name and valued with quantity.
@@ -85,19 +127,28 @@ func (r *resourceAllocationScorer) calculateResourceAllocatableRequest(nodeInfo
 		requested = nodeInfo.Requested
 	}
 
+	if r.batchResources != nil && r.batchResources.isBatchResource(resource) &&
+		(r.batchResources.priorityClassSelector == nil || r.batchResources.priorityClassSelector(pod)) {
+		return r.batchResources.calculateBatchAllocatableRequest(nodeInfo, pod, resource)
+	}
+
 	podRequest := r.calculatePodResourceRequest(pod, resource)
 	// If it's an extended resource, and the pod doesn't request it. We return (0, 0)
 	// as an implication to bypass scoring on this resource.
-	if podRequest == 0 && schedutil.IsScalarResourceName(resource) {
+	// deviceclass/ resources are claimed via pod.Spec.ResourceClaims rather than
+	// container.Resources.Requests, so they're exempt from this short-circuit.
+	if podRequest == 0 && schedutil.IsScalarResourceName(resource) && !strings.HasPrefix(string(resource), deviceClassResourcePrefix) {
 		return 0, 0
 	}
-	switch resource {
-	case v1.ResourceCPU:
+	switch {
+	case resource == v1.ResourceCPU:
 		return nodeInfo.Allocatable.MilliCPU, (requested.MilliCPU + podRequest)
-	case v1.ResourceMemory:
+	case resource == v1.ResourceMemory:
 		return nodeInfo.Allocatable.Memory, (requested.Memory + podRequest)
-	case v1.ResourceEphemeralStorage:
+	case resource == v1.ResourceEphemeralStorage:
 		return nodeInfo.Allocatable.EphemeralStorage, (nodeInfo.Requested.EphemeralStorage + podRequest)
+	case strings.HasPrefix(string(resource), deviceClassResourcePrefix):
+		return r.calculateDeviceClassAllocatableRequest(nodeInfo, pod, resource)
 	default:
 		if _, exists := nodeInfo.Allocatable.ScalarResources[resource]; exists {
 			return nodeInfo.Allocatable.ScalarResources[resource], (nodeInfo.Requested.ScalarResources[resource] + podRequest)
@@ -107,6 +158,46 @@ func (r *resourceAllocationScorer) calculateResourceAllocatableRequest(nodeInfo
 	return 0, 0
 }
 
+// calculateDeviceClassAllocatableRequest resolves a "deviceclass/<classname>"
+// ResourceSpec entry to the ResourceClass the pod's claims reference, and asks
+// claimAvailabilityProvider how many equivalent resources the node still has
+// free. It returns a neutral (0, 0) score contribution whenever the class
+// can't be resolved, rather than failing the node outright, since a missing
+// class is far more likely to be a stale profile than a genuine mismatch.
+func (r *resourceAllocationScorer) calculateDeviceClassAllocatableRequest(nodeInfo *framework.NodeInfo, pod *v1.Pod, resource v1.ResourceName) (int64, int64) {
+	if r.resourceClassLister == nil || r.claimAvailabilityProvider == nil {
+		return 0, 0
+	}
+	node := nodeInfo.Node()
+	className := strings.TrimPrefix(string(resource), deviceClassResourcePrefix)
+	if len(pod.Spec.ResourceClaims) == 0 {
+		return 0, 0
+	}
+
+	class, err := r.resourceClassLister.Get(className)
+	if err != nil {
+		klog.V(5).InfoS("ResourceClass not found, using neutral DRA score", "class", className, "pod", klog.KObj(pod))
+		return 0, 0
+	}
+	available, err := r.claimAvailabilityProvider.Available(node, class)
+	if err != nil {
+		klog.V(5).InfoS("Failed to query claim availability, using neutral DRA score", "class", className, "err", err)
+		return 0, 0
+	}
+
+	// Only count the claims that actually resolve to this class: a pod with
+	// claims spanning multiple classes must not be counted as demand against
+	// every class it claims from.
+	claimCount := r.claimAvailabilityProvider.ClaimsForClass(pod, class)
+	if claimCount == 0 {
+		return 0, 0
+	}
+
+	// Every claim referencing this class consumes one equivalent unit of the
+	// node's remaining availability for it.
+	return available, claimCount
+}
+
 // calculatePodResourceRequest returns the total non-zero requests. If Overhead is defined for the pod
 // the Overhead is added to the result.
 // podResourceRequest = max(sum(podSpec.Containers), podSpec.InitContainers) + overHead
@@ -135,15 +226,44 @@ func (r *resourceAllocationScorer) calculatePodResourceRequest(pod *v1.Pod, reso
 	//调度程序、资源配额处理以及 Kubelet 的 pod cgroup 创建和驱逐处理将考虑Overhead，以及 pod 的容器请求的总和。
 	//水平和垂直自动缩放是根据容器级别的统计数据计算的，因此不应受到 pod Overhead的影响。
 	//例如调度程序的calculatePodResourceRequest函数在计算某个pod的资源requst请求时，若pod的Overhead请求定义了，则需要将Overhead的值加入request统计。
-	if pod.Spec.Overhead != nil {
-		if quantity, found := pod.Spec.Overhead[resource]; found {
-			podRequest += quantity.Value()
+	if overhead, source := r.podOverhead(pod); overhead != nil {
+		if quantity, found := overhead[resource]; found {
+			qosFactor := r.qosWeightFor(pod)
+			klog.V(4).InfoS("Applying QoS-weighted overhead to pod request", "pod", klog.KObj(pod),
+				"resource", resource, "qosFactor", qosFactor, "overheadSource", source)
+			podRequest += int64(float64(quantity.Value()) * qosFactor)
 		}
 	}
 
 	return podRequest
 }
 
+// podOverhead returns the overhead to apply for pod, preferring the
+// RuntimeClass's PodFixed overhead when the pod specifies a RuntimeClassName
+// and runtimeClassLister resolves it — Kata/gVisor pods often understate
+// overhead at admission — and otherwise falling back to pod.Spec.Overhead.
+// The second return value names the source, for the klog summary line.
+func (r *resourceAllocationScorer) podOverhead(pod *v1.Pod) (v1.ResourceList, string) {
+	if r.runtimeClassLister != nil && pod.Spec.RuntimeClassName != nil {
+		if rc, err := r.runtimeClassLister.Get(*pod.Spec.RuntimeClassName); err == nil && rc.Overhead != nil {
+			return rc.Overhead.PodFixed, "runtimeClass"
+		}
+	}
+	return pod.Spec.Overhead, "podSpec"
+}
+
+// qosWeightFor returns the configured weight for pod's QoS class, defaulting
+// to 1.0 (no scaling) when qosWeights is unset or doesn't cover the class.
+func (r *resourceAllocationScorer) qosWeightFor(pod *v1.Pod) float64 {
+	if r.qosWeights == nil {
+		return 1.0
+	}
+	if weight, ok := r.qosWeights[v1qos.GetPodQOS(pod)]; ok {
+		return weight
+	}
+	return 1.0
+}
+
 //podResourceRequest = max(sum(podSpec.Containers), podSpec.InitContainers) + overHead
 //上面的公式取了常规容器和 每个init 容器的最大值。因为 init 容器是按顺序运行的，多个init也是按顺序运行的。相反，对常规容器的资源向量求和，因为它们是同时运行。
 //GetRequestForResource函数的参数 !r.useRequested 相当于 nonZero 取反后bool值等价。
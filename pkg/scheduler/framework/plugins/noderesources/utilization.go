@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// UtilizationScorerName is the name under which the target-load-packing
+// scorer is registered.
+const UtilizationScorerName = "Utilization"
+
+// utilizationTarget pairs a resource's weight with the utilization percentage
+// (0-100) at which it scores highest.
+type utilizationTarget struct {
+	weight int64
+	target float64
+}
+
+// utilizationResourceAllocationScorer scores nodes on their *observed*
+// CPU/memory utilization rather than on requested/allocatable ratios, using
+// a target-load-packing curve: score rises linearly to TargetUtilization and
+// falls linearly back to zero at 100%, so pods pack onto hot-but-not-saturated
+// nodes instead of either cold (wasteful) or near-full (risky) ones.
+type utilizationResourceAllocationScorer struct {
+	resourceAllocationScorer
+	usageProvider NodeUsageProvider
+	targets       map[v1.ResourceName]utilizationTarget
+}
+
+// NewUtilizationScorer builds a scorer that falls back to request-based
+// scoring for any node whose usage can't currently be read from provider.
+// The fallback keeps base's request accounting, so the DRA, batch-resource,
+// QoS-weight and RuntimeClass-overhead wiring NewFit layered onto base carries
+// over, but scores the result with score below rather than base's own scorer.
+func NewUtilizationScorer(provider NodeUsageProvider, base *resourceAllocationScorer, args *config.NodeResourcesFitArgs) *utilizationResourceAllocationScorer {
+	targets := make(map[v1.ResourceName]utilizationTarget, len(args.UtilizationTargets))
+	for _, t := range args.UtilizationTargets {
+		targets[v1.ResourceName(t.Name)] = utilizationTarget{weight: t.Weight, target: t.Target}
+	}
+	s := &utilizationResourceAllocationScorer{
+		resourceAllocationScorer: *base,
+		usageProvider:            newCachingNodeUsageProvider(provider),
+		targets:                  targets,
+	}
+	s.Name = UtilizationScorerName
+	s.scorer = s.score
+	return s
+}
+
+// score implements the scorer signature used by resourceAllocationScorer.score:
+// the weighted average of requested/allocatable as a percentage. NodeScore uses
+// it only in its request-based fallback; the live-utilization path does not.
+func (s *utilizationResourceAllocationScorer) score(requested, allocable resourceToValueMap) int64 {
+	var nodeScore, weightSum int64
+	for resource, alloc := range allocable {
+		weight := s.resourceToWeightMap[resource]
+		weightSum += weight
+		if alloc == 0 {
+			continue
+		}
+		nodeScore += int64(float64(requested[resource])/float64(alloc)*100) * weight
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return nodeScore / weightSum
+}
+
+// NodeScore scores nodeName using live utilization when available, falling
+// back to the embedded resourceAllocationScorer's request-based score
+// otherwise.
+func (s *utilizationResourceAllocationScorer) NodeScore(pod *v1.Pod, nodeInfo *framework.NodeInfo) (int64, *framework.Status) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return 0, framework.NewStatus(framework.Error, "node not found")
+	}
+
+	usage, err := s.usageProvider.NodeUsage(node.Name)
+	if err != nil {
+		klog.V(4).InfoS("No live utilization available for node, falling back to request-based scoring", "node", klog.KObj(node), "err", err)
+		return s.resourceAllocationScorer.score(pod, nodeInfo)
+	}
+
+	var weighted, weightSum int64
+	for resource, target := range s.targets {
+		observed, ok := usage[resource]
+		if !ok {
+			continue
+		}
+		weighted += targetLoadPackingScore(observed*100, target.target) * target.weight
+		weightSum += target.weight
+	}
+	if weightSum == 0 {
+		return s.resourceAllocationScorer.score(pod, nodeInfo)
+	}
+	return weighted / weightSum, nil
+}
+
+// targetLoadPackingScore implements the target-load-packing curve: it rises
+// linearly from 0 at 0% utilization to framework.MaxNodeScore at target%, then
+// falls linearly back to 0 at 100%.
+func targetLoadPackingScore(utilizationPercent, target float64) int64 {
+	if target <= 0 || target >= 100 {
+		target = 70
+	}
+	if utilizationPercent <= target {
+		return int64(utilizationPercent / target * float64(framework.MaxNodeScore))
+	}
+	if utilizationPercent >= 100 {
+		return 0
+	}
+	return int64((100 - utilizationPercent) / (100 - target) * float64(framework.MaxNodeScore))
+}